@@ -24,6 +24,7 @@ func (s *BackendState) ResetEvents() {
 	s.keyPresses = s.keyPresses[:0]
 	s.clicks = 0
 	s.clickButtons = 0
+	s.lastButtons = s.mouseButtons
 	if s.drop {
 		s.drag = nil
 		s.drop = false
@@ -41,6 +42,7 @@ func (s *BackendState) AddKeyPress(k Key)             { s.keyPresses = append(s.
 func (s *BackendState) AddTextInput(text string)      { s.textInput += text }
 func (s *BackendState) SetBlink(b bool)               { s.blink = b }
 func (s *BackendState) SetWindowSize(w, h int)        { s.windowSize = draw.WH(w, h) }
+func (s *BackendState) SetWaker(wake func())          { s.wake = wake }
 
 func (s *BackendState) GrabMouse() {
 	s.grabbed = s.hoveredC
@@ -14,6 +14,32 @@ type Outline struct {
 	Color Color
 }
 
+// RoundedFill fills a rectangle with rounded corners.
+type RoundedFill struct {
+	Rect   image.Rectangle
+	Radius int
+	Color  Color
+}
+
+// Border draws a border of the given width along the inside of a rectangle, with rounded corners.
+type Border struct {
+	Rect   image.Rectangle
+	Width  int
+	Radius int
+	Color  Color
+}
+
+// Gradient fills a rectangle with rounded corners with a color gradient.
+// A linear gradient changes from StartColor at Start to EndColor at End,
+// a radial gradient from StartColor at Start to EndColor at the distance between Start and End.
+type Gradient struct {
+	Rect                 image.Rectangle
+	Radius               int
+	Start, End           image.Point
+	StartColor, EndColor Color
+	Radial               bool
+}
+
 type Text struct {
 	Position image.Point
 	Text     string
@@ -40,10 +66,19 @@ type Image struct {
 	Update bool
 }
 
+// A CommandList contains commands that are drawn with the same offset, clip and transform.
+// The commands are translated by Offset, and then transformed by Transform.
+// Clip is not transformed, it is a rectangle on the screen.
+//
+// If Layer is not nil, the CommandList contains no commands. Instead, the lists in Layer are drawn
+// into a separate layer, which is then drawn with the given Opacity.
 type CommandList struct {
-	Commands []Command
-	Offset   image.Point
-	Clip     image.Rectangle
+	Commands  []Command
+	Offset    image.Point
+	Clip      image.Rectangle
+	Transform Transform
+	Opacity   float32
+	Layer     []CommandList
 }
 
 // A Buffer contains a list of commands.
@@ -52,12 +87,21 @@ type Buffer struct {
 	FontLookup FontLookup
 	All        []CommandList
 	state
-	stack []state
+	stack []frame
 }
 
 type state struct {
-	clip   image.Rectangle
-	bounds image.Rectangle
+	clip      image.Rectangle // in screen coordinates
+	bounds    image.Rectangle
+	transform Transform
+}
+
+// frame is the state saved by a call to one of the Push methods.
+type frame struct {
+	state
+	layer   bool
+	opacity float32
+	all     []CommandList
 }
 
 // Reset clears the command list and sets the size of the drawing area.
@@ -67,6 +111,7 @@ func (b *Buffer) Reset(w, h int) {
 	b.stack = b.stack[:0]
 	b.bounds = WH(w, h)
 	b.clip = WH(w, h)
+	b.transform = Identity
 }
 
 // Push constrains drawing to a rectangle.
@@ -74,18 +119,48 @@ func (b *Buffer) Reset(w, h int) {
 // Subsequent calls to Size will return the rectangle's size.
 func (b *Buffer) Push(r image.Rectangle) {
 	b.flush()
-	b.stack = append(b.stack, b.state)
+	b.stack = append(b.stack, frame{state: b.state})
 	r = r.Add(b.bounds.Min)
-	b.clip = b.clip.Intersect(r)
+	b.clip = b.clip.Intersect(b.transform.Bounds(r))
 	b.bounds = r
 }
 
-// Pop undoes the last call to Push.
+// PushTransform transforms subsequent operations, relative to the top left corner of the current drawing area.
+// Clipping is done on the bounding box of the transformed drawing area.
+// Subsequent calls to Size will return the same size as before.
+func (b *Buffer) PushTransform(t Transform) {
+	b.flush()
+	b.stack = append(b.stack, frame{state: b.state})
+	min := b.bounds.Min
+	b.transform = b.transform.Mul(Translate(float32(min.X), float32(min.Y))).Mul(t)
+	b.bounds = b.bounds.Sub(min)
+}
+
+// PushOpacity makes subsequent operations translucent.
+// Unlike drawing with translucent colors, overlapping commands do not show through each other,
+// they are drawn into a separate layer that is then drawn with the given opacity.
+func (b *Buffer) PushOpacity(opacity float32) {
+	b.flush()
+	b.stack = append(b.stack, frame{state: b.state, layer: opacity < 1, opacity: opacity, all: b.All})
+	if opacity < 1 {
+		b.All = nil
+	}
+}
+
+// Pop undoes the last call to Push, PushTransform or PushOpacity.
 func (b *Buffer) Pop() {
 	b.flush()
 	l := len(b.stack) - 1
 	if l >= 0 {
-		b.state, b.stack = b.stack[l], b.stack[:l]
+		f := b.stack[l]
+		b.state, b.stack = f.state, b.stack[:l]
+		if f.layer {
+			layer := b.All
+			b.All = f.all
+			if len(layer) > 0 && f.opacity > 0 {
+				b.All = append(b.All, CommandList{Clip: f.clip, Transform: Identity, Opacity: f.opacity, Layer: layer})
+			}
+		}
 	}
 }
 
@@ -94,6 +169,15 @@ func (b *Buffer) Size() (int, int) {
 	return b.bounds.Dx(), b.bounds.Dy()
 }
 
+// Clip returns the part of the current drawing area that is visible.
+// Components can use this to avoid generating commands that would be clipped anyway.
+func (b *Buffer) Clip() image.Rectangle {
+	if b.transform == Identity || b.clip.Empty() {
+		return b.clip.Sub(b.bounds.Min)
+	}
+	return b.transform.Invert().Bounds(b.clip).Intersect(b.bounds).Sub(b.bounds.Min)
+}
+
 // Add adds commands to the buffer.
 func (b *Buffer) Add(c ...Command) {
 	b.Commands = append(b.Commands, c...)
@@ -113,6 +197,34 @@ func (b *Buffer) Outline(r image.Rectangle, c Color) {
 	}
 }
 
+// RoundedFill adds a command to fill a rectangle with rounded corners.
+func (b *Buffer) RoundedFill(r image.Rectangle, radius int, c Color) {
+	if !b.clip.Empty() {
+		b.Commands = append(b.Commands, RoundedFill{Rect: r, Radius: radius, Color: c})
+	}
+}
+
+// Border adds a command to draw a border inside a rectangle.
+func (b *Buffer) Border(r image.Rectangle, width, radius int, c Color) {
+	if !b.clip.Empty() && width > 0 {
+		b.Commands = append(b.Commands, Border{Rect: r, Width: width, Radius: radius, Color: c})
+	}
+}
+
+// LinearGradient adds a command to fill a rectangle with a gradient from c0 at start to c1 at end.
+func (b *Buffer) LinearGradient(r image.Rectangle, radius int, start, end image.Point, c0, c1 Color) {
+	if !b.clip.Empty() {
+		b.Commands = append(b.Commands, Gradient{Rect: r, Radius: radius, Start: start, End: end, StartColor: c0, EndColor: c1})
+	}
+}
+
+// RadialGradient adds a command to fill a rectangle with a gradient from c0 at center to c1 at distance size from the center.
+func (b *Buffer) RadialGradient(r image.Rectangle, radius int, center image.Point, size int, c0, c1 Color) {
+	if !b.clip.Empty() {
+		b.Commands = append(b.Commands, Gradient{Rect: r, Radius: radius, Start: center, End: center.Add(image.Pt(size, 0)), StartColor: c0, EndColor: c1, Radial: true})
+	}
+}
+
 // Text adds a command to render text.
 func (b *Buffer) Text(p image.Point, text string, c Color, font Font) {
 	if !b.clip.Empty() {
@@ -159,7 +271,7 @@ func (b *Buffer) SubImage(r image.Rectangle, i *image.RGBA, sub image.Rectangle,
 
 func (b *Buffer) flush() {
 	if len(b.Commands) > 0 {
-		b.All = append(b.All, CommandList{b.Commands, b.bounds.Min, b.clip})
+		b.All = append(b.All, CommandList{Commands: b.Commands, Offset: b.bounds.Min, Clip: b.clip, Transform: b.transform})
 		b.Commands = nil
 	}
 }
@@ -0,0 +1,78 @@
+package draw
+
+import (
+	"image"
+	"math"
+)
+
+// A Transform is an affine transformation.
+// It maps a point (x, y) to (t[0]*x + t[2]*y + t[4], t[1]*x + t[3]*y + t[5]).
+type Transform [6]float32
+
+// Identity is the transform that maps every point to itself.
+var Identity = Transform{1, 0, 0, 1, 0, 0}
+
+// Translate returns a transform that moves points by (x, y).
+func Translate(x, y float32) Transform {
+	return Transform{1, 0, 0, 1, x, y}
+}
+
+// Scale returns a transform that scales points relative to the origin.
+func Scale(x, y float32) Transform {
+	return Transform{x, 0, 0, y, 0, 0}
+}
+
+// Rotate returns a transform that rotates points around the origin.
+// The angle is in radians, positive angles rotate clockwise because the y axis points down.
+func Rotate(angle float32) Transform {
+	s, c := math.Sincos(float64(angle))
+	return Transform{float32(c), float32(s), float32(-s), float32(c), 0, 0}
+}
+
+// Mul returns a transform that applies u first, then t.
+func (t Transform) Mul(u Transform) Transform {
+	return Transform{
+		t[0]*u[0] + t[2]*u[1],
+		t[1]*u[0] + t[3]*u[1],
+		t[0]*u[2] + t[2]*u[3],
+		t[1]*u[2] + t[3]*u[3],
+		t[0]*u[4] + t[2]*u[5] + t[4],
+		t[1]*u[4] + t[3]*u[5] + t[5],
+	}
+}
+
+// Apply transforms a point.
+func (t Transform) Apply(p Point) Point {
+	return Point{t[0]*p.X + t[2]*p.Y + t[4], t[1]*p.X + t[3]*p.Y + t[5]}
+}
+
+// Invert returns the inverse transform. If t can not be inverted, the result is undefined.
+func (t Transform) Invert() Transform {
+	d := t[0]*t[3] - t[1]*t[2]
+	a, b, c, e := t[3]/d, -t[1]/d, -t[2]/d, t[0]/d
+	return Transform{a, b, c, e, -(a*t[4] + c*t[5]), -(b*t[4] + e*t[5])}
+}
+
+// Bounds returns the smallest rectangle that contains the transformed rectangle r.
+func (t Transform) Bounds(r image.Rectangle) image.Rectangle {
+	if t == Identity || r.Empty() {
+		return r
+	}
+	corners := [4]Point{
+		t.Apply(Point{float32(r.Min.X), float32(r.Min.Y)}),
+		t.Apply(Point{float32(r.Max.X), float32(r.Min.Y)}),
+		t.Apply(Point{float32(r.Min.X), float32(r.Max.Y)}),
+		t.Apply(Point{float32(r.Max.X), float32(r.Max.Y)}),
+	}
+	min, max := corners[0], corners[0]
+	for _, p := range corners[1:] {
+		min.X, min.Y = float32(math.Min(float64(min.X), float64(p.X))), float32(math.Min(float64(min.Y), float64(p.Y)))
+		max.X, max.Y = float32(math.Max(float64(max.X), float64(p.X))), float32(math.Max(float64(max.Y), float64(p.Y)))
+	}
+	// small rounding errors should not make the rectangle larger
+	const e = 1e-3
+	return image.Rect(
+		int(math.Floor(float64(min.X+e))), int(math.Floor(float64(min.Y+e))),
+		int(math.Ceil(float64(max.X-e))), int(math.Ceil(float64(max.Y-e))),
+	)
+}
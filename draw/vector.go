@@ -0,0 +1,134 @@
+package draw
+
+// A Point is a position with sub-pixel precision, used by vector drawing commands.
+type Point struct {
+	X, Y float32
+}
+
+// Pt is shorthand for Point{X: x, Y: y}.
+func Pt(x, y float32) Point {
+	return Point{x, y}
+}
+
+// Polyline draws connected line segments. If Closed is true, the last point is connected to the first.
+type Polyline struct {
+	Points []Point
+	Width  float32
+	Color  Color
+	Closed bool
+}
+
+// Polygon fills the area enclosed by a sequence of points.
+type Polygon struct {
+	Points []Point
+	Color  Color
+}
+
+// Ellipse draws the outline of an ellipse, or fills it if Width is 0.
+type Ellipse struct {
+	Center Point
+	RX, RY float32
+	Width  float32
+	Color  Color
+}
+
+// Arc draws a part of the outline of an ellipse, or fills a pie slice if Width is 0.
+// Angles are in radians, starting at the positive x axis and increasing clockwise.
+type Arc struct {
+	Center     Point
+	RX, RY     float32
+	Start, End float32
+	Width      float32
+	Color      Color
+}
+
+// Bezier draws a path of cubic Bézier curves, or fills the area enclosed by it if Width is 0.
+// Points contains the start point, followed by two control points and an end point for each curve.
+type Bezier struct {
+	Points []Point
+	Width  float32
+	Color  Color
+}
+
+// Line adds a command to draw a line.
+func (b *Buffer) Line(p, q Point, width float32, c Color) {
+	b.Polyline([]Point{p, q}, width, c)
+}
+
+// Polyline adds a command to draw connected line segments.
+func (b *Buffer) Polyline(points []Point, width float32, c Color) {
+	if !b.clip.Empty() && width > 0 && len(points) > 1 {
+		b.Commands = append(b.Commands, Polyline{Points: copyPoints(points), Width: width, Color: c})
+	}
+}
+
+// FillPolygon adds a command to fill a polygon.
+func (b *Buffer) FillPolygon(points []Point, c Color) {
+	if !b.clip.Empty() && len(points) > 2 {
+		b.Commands = append(b.Commands, Polygon{Points: copyPoints(points), Color: c})
+	}
+}
+
+// OutlinePolygon adds a command to outline a polygon.
+func (b *Buffer) OutlinePolygon(points []Point, width float32, c Color) {
+	if !b.clip.Empty() && width > 0 && len(points) > 1 {
+		b.Commands = append(b.Commands, Polyline{Points: copyPoints(points), Width: width, Color: c, Closed: true})
+	}
+}
+
+// FillEllipse adds a command to fill an ellipse.
+func (b *Buffer) FillEllipse(center Point, rx, ry float32, c Color) {
+	if !b.clip.Empty() {
+		b.Commands = append(b.Commands, Ellipse{Center: center, RX: rx, RY: ry, Color: c})
+	}
+}
+
+// OutlineEllipse adds a command to outline an ellipse.
+func (b *Buffer) OutlineEllipse(center Point, rx, ry, width float32, c Color) {
+	if !b.clip.Empty() && width > 0 {
+		b.Commands = append(b.Commands, Ellipse{Center: center, RX: rx, RY: ry, Width: width, Color: c})
+	}
+}
+
+// FillCircle adds a command to fill a circle.
+func (b *Buffer) FillCircle(center Point, r float32, c Color) {
+	b.FillEllipse(center, r, r, c)
+}
+
+// OutlineCircle adds a command to outline a circle.
+func (b *Buffer) OutlineCircle(center Point, r, width float32, c Color) {
+	b.OutlineEllipse(center, r, r, width, c)
+}
+
+// Arc adds a command to draw a part of an ellipse's outline, from angle start to angle end.
+func (b *Buffer) Arc(center Point, rx, ry, start, end, width float32, c Color) {
+	if !b.clip.Empty() && width > 0 {
+		b.Commands = append(b.Commands, Arc{Center: center, RX: rx, RY: ry, Start: start, End: end, Width: width, Color: c})
+	}
+}
+
+// FillPie adds a command to fill a pie slice of an ellipse, from angle start to angle end.
+func (b *Buffer) FillPie(center Point, rx, ry, start, end float32, c Color) {
+	if !b.clip.Empty() {
+		b.Commands = append(b.Commands, Arc{Center: center, RX: rx, RY: ry, Start: start, End: end, Color: c})
+	}
+}
+
+// Bezier adds a command to draw a path of cubic Bézier curves.
+func (b *Buffer) Bezier(points []Point, width float32, c Color) {
+	if !b.clip.Empty() && width > 0 && len(points) >= 4 {
+		b.Commands = append(b.Commands, Bezier{Points: copyPoints(points), Width: width, Color: c})
+	}
+}
+
+// FillBezier adds a command to fill the area enclosed by a path of cubic Bézier curves.
+func (b *Buffer) FillBezier(points []Point, c Color) {
+	if !b.clip.Empty() && len(points) >= 4 {
+		b.Commands = append(b.Commands, Bezier{Points: copyPoints(points), Color: c})
+	}
+}
+
+// copyPoints copies the points of a command, so that the caller can reuse the slice.
+func copyPoints(p []Point) []Point {
+	return append([]Point(nil), p...)
+}
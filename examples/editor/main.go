@@ -3,6 +3,8 @@ package main
 import (
 	"os"
 	"path/filepath"
+	"strconv"
+	"time"
 
 	"github.com/jfreymuth/ui"
 	"github.com/jfreymuth/ui/draw"
@@ -15,6 +17,7 @@ import (
 type Editor struct {
 	editor *TextArea
 	files  *FileChooser
+	status *StatusBar
 
 	unsavedChanges bool
 	filePath       string
@@ -26,24 +29,84 @@ func main() {
 	e.editor.Font = draw.Font{Name: "gomono", Size: 11}
 	e.files = NewFileChooser()
 	e.files.SetPath(".")
+	e.files.SetFilters(
+		FileFilter{Name: "All files"},
+		FileFilter{Name: "Text files (*.txt, *.md)", Patterns: []string{"*.txt", "*.md"}},
+		FileFilter{Name: "Go files (*.go)", Patterns: []string{"*.go"}},
+	)
 
 	menuBar := NewMenuBar()
-	fileMenu := menuBar.AddMenu("File")
-	fileMenu.AddItemIcon("add", "New", e.New)
-	fileMenu.AddItemIcon("open", "Open...", e.ShowOpenDialog)
-	fileMenu.AddItemIcon("save", "Save", e.Save)
-	fileMenu.AddItemIcon("save", "Save As...", e.ShowSaveDialog)
-	fileMenu.AddItemIcon("close", "Exit", func(state *ui.State) { e.DoDestructive(state, (*ui.State).Quit) })
-	editMenu := menuBar.AddMenu("Edit")
-	editMenu.AddItemIcon("", "Select All", e.editor.SelectAll)
-	editMenu.AddItemIcon("cut", "Cut", e.editor.Cut)
-	editMenu.AddItemIcon("copy", "Copy", e.editor.Copy)
-	editMenu.AddItemIcon("paste", "Paste", e.editor.Paste)
+	fileMenu := menuBar.AddMenu("&File")
+	fileMenu.AddItemIcon("add", "&New", e.New)
+	open := fileMenu.AddItemIcon("open", "&Open...", e.ShowOpenDialog)
+	fileMenu.AddSeparator()
+	save := fileMenu.AddItemIcon("save", "&Save", e.Save)
+	saveAs := fileMenu.AddItemIcon("save", "Save &As...", e.ShowSaveDialog)
+	fileMenu.AddSeparator()
+	fileMenu.AddItemIcon("close", "E&xit", func(state *ui.State) { e.DoDestructive(state, (*ui.State).Quit) })
+	editMenu := menuBar.AddMenu("&Edit")
+	cut := editMenu.AddItemIcon("cut", "Cu&t", e.editor.Cut)
+	cut.Accelerator = "Ctrl+X"
+	copy := editMenu.AddItemIcon("copy", "&Copy", e.editor.Copy)
+	copy.Accelerator = "Ctrl+C"
+	editMenu.AddItemIcon("paste", "&Paste", e.editor.Paste).Accelerator = "Ctrl+V"
+	editMenu.AddSeparator()
+	editMenu.AddItemIcon("", "Select &All", e.editor.SelectAll).Accelerator = "Ctrl+A"
+	editMenu.AboutToShow = func(state *ui.State, m *Menu) {
+		// Cut and Copy are only useful if some text is selected
+		empty := e.editor.SelectedText() == ""
+		cut.Disabled, copy.Disabled = empty, empty
+	}
+	viewMenu := menuBar.AddMenu("&View")
+	viewMenu.AddCheckItem("Insert &Spaces", false, func(*ui.State) { e.editor.InsertSpaces = !e.editor.InsertSpaces })
+	viewMenu.AddCheckItem("Auto &Indent", true, func(*ui.State) { e.editor.AutoIndent = !e.editor.AutoIndent })
+	viewMenu.AddSeparator()
+	for _, w := range []int{2, 4, 8} {
+		w := w
+		viewMenu.AddRadioItem("Tab Width "+strconv.Itoa(w), w == e.editor.TabWidth, func(*ui.State) { e.editor.TabWidth = w })
+	}
+
+	toolBar := NewToolBar()
+	toolBar.AddButton("add", "New", e.New)
+	toolBar.AddButton("open", "Open", e.ShowOpenDialog)
+	toolBar.AddButton("save", "Save", e.Save)
+	toolBar.AddSeparator()
+	toolBar.AddButton("cut", "Cut", e.editor.Cut)
+	toolBar.AddButton("copy", "Copy", e.editor.Copy)
+	toolBar.AddButton("paste", "Paste", e.editor.Paste)
+
+	e.status = NewStatusBar()
+	file := e.status.AddSegment("", true)
+	position := e.status.AddSegment("", false)
+	position.Width = 80
+	modified := e.status.AddSegment("", false)
+	modified.Width = 60
+	e.status.Refresh = func(state *ui.State) {
+		// The status bar is updated after the text area, so it always shows the current state
+		if e.editor.Changed() {
+			e.unsavedChanges = true
+		}
+		file.Text = "Untitled"
+		if e.filePath != "" {
+			file.Text = e.filePath
+		}
+		line, col := e.editor.CursorPosition()
+		position.Text = "Ln " + strconv.Itoa(line+1) + ", Col " + strconv.Itoa(col+1)
+		modified.Text = ""
+		if e.unsavedChanges {
+			modified.Text = "Modified"
+		}
+	}
 
 	root := NewRoot(&Container{
-		Top:    menuBar,
+		Top:    NewStack(menuBar, toolBar),
 		Center: NewScrollView(e.editor),
+		Bottom: e.status,
 	})
+	// Shortcuts registered for menu items are shown in the menu
+	root.Shortcuts.AddMenuItem("Ctrl+O", open)
+	root.Shortcuts.AddMenuItem("Ctrl+S", save)
+	root.Shortcuts.AddMenuItem("Ctrl+Shift+S", saveAs)
 
 	sdl.Show(sdl.Options{
 		Title: "Editor",
@@ -60,21 +123,6 @@ func main() {
 			}
 		},
 		Update: func(state *ui.State) {
-			// Handle custom keyboard shortcuts (Ctrl+O, Ctrl+S)
-			if state.HasModifiers(ui.Control) {
-				for _, k := range state.PeekKeyPresses() {
-					switch k {
-					case ui.KeyS:
-						if state.HasModifiers(ui.Shift) {
-							e.ShowSaveDialog(state)
-						} else {
-							e.Save(state)
-						}
-					case ui.KeyO:
-						e.ShowOpenDialog(state)
-					}
-				}
-			}
 			// Handle default keyboard shortcuts (copy, paste etc.)
 			ui.HandleKeyboardShortcuts(state)
 		},
@@ -145,6 +193,7 @@ func (e *Editor) SaveAs(state *ui.State, path string) {
 	e.editor.Changed()
 	e.unsavedChanges = false
 	e.filePath = path
+	e.status.ShowMessage(state, "Saved "+filepath.Base(path), 3*time.Second)
 }
 
 // If there are unsaved changes, asks the user for confirmation.
@@ -1,8 +1,14 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"image"
+	"math"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/jfreymuth/ui"
 	"github.com/jfreymuth/ui/draw"
@@ -61,6 +67,38 @@ func main() {
 				ShowMessageDialog(state, "Message", "Quit without saving", "Ok")
 			})
 		})
+		// the frame is reused, so the dialog opens where it was last closed
+		props := NewForm()
+		props.AddField("Name:", NewTextField())
+		props.AddField("Visible:", NewCheckBox("Yes"))
+		propsDialog := NewFrame("settings", "Properties", &Container{
+			Center: props,
+			Bottom: NewBar(-1, NewButton("Close", (*ui.State).CloseDialog)),
+		}, DefaultTheme.Color("titleBackground"))
+		propsDialog.Movable, propsDialog.Resizable = true, true
+		propsDialog.MinWidth, propsDialog.MinHeight = 200, 80
+		menu.AddItem("Properties", func(state *ui.State) {
+			state.OpenDialog(propsDialog)
+		})
+		menu.AddItem("Progress", func(state *ui.State) {
+			ShowProgressDialog(state, "Progress", func(ctx context.Context, report func(float32, string)) error {
+				for i := 0; i <= 100; i++ {
+					select {
+					case <-ctx.Done():
+						return ctx.Err()
+					case <-time.After(30 * time.Millisecond):
+					}
+					report(float32(i)/100, fmt.Sprint("Step ", i, " of 100"))
+				}
+				return errors.New("this is what happens if the work fails")
+			})
+		})
+		menu.AddItem("Notification", func(state *ui.State) {
+			n := Notify(state, "info", "Notification", "This is a notification.\nIt disappears after 5 seconds.", 5*time.Second)
+			n.AddAction("Show Dialog", func(state *ui.State) {
+				ShowMessageDialog(state, "Message", "Action clicked.", "Ok")
+			})
+		})
 		menu.AddItem("Input", func(state *ui.State) {
 			ShowInputDialog(state, "Input", "Input something", "Ok", "Cancel", func(state *ui.State, text string) {
 				ShowMessageDialog(state, "Message", fmt.Sprint("Your input: ", text), "Ok")
@@ -71,8 +109,20 @@ func main() {
 				ShowMessageDialog(state, "Message", fmt.Sprint("Your input: ", path), "Ok")
 			})
 		})
+		menu.AddItem("Open Files", func(state *ui.State) {
+			ShowOpenFilesDialog(state, NewFileChooser(), "Open", "Open", "Cancel", func(state *ui.State, paths []string) {
+				ShowMessageDialog(state, "Message", fmt.Sprint("Your input: ", strings.Join(paths, "\n")), "Ok")
+			})
+		})
+		menu.AddItem("Choose Folder", func(state *ui.State) {
+			ShowDirectoryDialog(state, NewFileChooser(), "Output Folder", "Choose", "Cancel", func(state *ui.State, path string) {
+				ShowMessageDialog(state, "Message", fmt.Sprint("Your input: ", path), "Ok")
+			})
+		})
 		menu.AddItem("Save", func(state *ui.State) {
-			ShowSaveDialog(state, NewFileChooser(), "Save As", "Save", "Cancel", func(state *ui.State, path string) {
+			fc := NewFileChooser()
+			fc.DefaultExtension = ".txt"
+			ShowSaveDialog(state, fc, "Save As", "Save", "Cancel", func(state *ui.State, path string) {
 				ShowMessageDialog(state, "Message", fmt.Sprint("Your input: ", path), "Ok")
 			})
 		})
@@ -112,18 +162,48 @@ func main() {
 	form.AddField("ComboBox:", cb)
 	form.AddField("Theme:", themeButton)
 
+	toolBar := NewToolBar()
+	toolBar.AddButton("cut", "Cut", ta.Cut)
+	toolBar.AddButton("copy", "Copy", ta.Copy)
+	toolBar.AddButton("paste", "Paste", ta.Paste)
+	toolBar.AddSeparator()
+	toolBar.AddToggleButton("edit", "Editable", true, func(*ui.State) { ta.Editable = !ta.Editable })
+	toolBar.AddSeparator()
+	toolBar.AddComponent("Font:", font)
+	toolBar.AddComponent("Size:", size)
 	text := &Container{
 		Center: NewScrollView(ta),
-		Bottom: NewBar(100,
-			NewButtonIcon("cut", "", ta.Cut),
-			NewButtonIcon("copy", "", ta.Copy),
-			NewButtonIcon("paste", "", ta.Paste),
-			NewSeparator(3, 1),
-			NewLabel(" Font: "), font,
-			NewLabel(" Size: "), size,
-		),
+		Bottom: toolBar,
 	}
 	tabs.AddTab("Test", NewHorizontalDivider(NewScrollView(form), text))
+	img := image.NewRGBA(image.Rect(0, 0, 256, 256))
+	for i := 0; i < len(img.Pix); i += 4 {
+		x, y := i/4%256, i/4/256
+		a := 255 - x*y/255 // colors are premultiplied
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = uint8(x*a/255), uint8(y*a/255), uint8((255-x)*a/255), uint8(a)
+	}
+	imageView := NewImageView(img)
+	imageView.Checkerboard = true
+	tabs.AddClosableTab("Image", imageView, nil)
+	tabs.AddClosableTab("Vector", NewCanvas(300, 200, func(g *draw.Buffer, state *ui.State) {
+		w, h := g.Size()
+		g.LinearGradient(draw.XYXY(190, 10, w-10, 70), 8, image.Pt(190, 10), image.Pt(w-10, 70), draw.RGBA(1, .9, .6, 1), draw.RGBA(.6, .8, 1, 1))
+		g.RadialGradient(draw.XYXY(190, 80, w-10, 170), 8, image.Pt(240, 125), 80, draw.White, draw.RGBA(.4, .4, .8, 1))
+		g.Border(draw.XYXY(190, 80, w-10, 170), 2, 8, draw.Gray(.4))
+		g.FillPie(draw.Pt(100, 100), 80, 80, 0, 4, draw.RGBA(.2, .5, 1, 1))
+		g.FillPie(draw.Pt(100, 100), 80, 80, 4, 2*math.Pi, draw.RGBA(1, .5, .2, 1))
+		g.OutlineCircle(draw.Pt(100, 100), 80, 2, draw.Gray(.5))
+		g.FillPolygon([]draw.Point{{200, 40}, {260, 60}, {230, 90}, {280, 150}, {210, 120}}, draw.RGBA(.3, .8, .3, .7))
+		g.Bezier([]draw.Point{{20, float32(h) - 20}, {150, float32(h) - 200}, {float32(w) - 150, float32(h)}, {float32(w) - 20, 40}}, 3, draw.RGBA(.8, .2, .6, 1))
+		g.PushTransform(draw.Translate(30, 190).Mul(draw.Rotate(-math.Pi / 12)))
+		g.PushOpacity(.6)
+		g.Fill(draw.WH(60, 20), draw.RGBA(1, 1, .5, 1))
+		g.Text(image.Pt(5, 15), "Rotated", draw.Black, draw.Font{Name: "default", Size: 12})
+		g.Pop()
+		g.Pop()
+		mouse := state.MousePos()
+		g.Line(draw.Pt(float32(w)/2, float32(h)/2), draw.Pt(float32(mouse.X), float32(mouse.Y)), 1.5, draw.Gray(.3))
+	}), nil)
 	tabs.AddClosableTab("More", NewLabel("Second tab"), nil)
 	tabs.AddClosableTab("Tabs", NewLabel("Third tab"), func(state *ui.State, tabIndex int) {
 		ShowConfirmDialog(state, "Confirm", "Close the tab?", "Close", "Cancel", func(*ui.State) { tabs.CloseTab(tabIndex) })
@@ -1,6 +1,6 @@
 module github.com/jfreymuth/ui
 
-go 1.12
+go 1.16
 
 require (
 	github.com/go-gl/gl v0.0.0-20190320180904-bf2b1f2f34d7
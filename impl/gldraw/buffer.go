@@ -17,6 +17,7 @@ type buffer struct {
 	buf      []vertex
 	program  uint32
 	sizeLoc  int32
+	transLoc int32
 }
 
 func (b *buffer) init(cap int) {
@@ -24,13 +25,14 @@ func (b *buffer) init(cap int) {
 	b.program = createProgram(vss, fss)
 	gl.UseProgram(b.program)
 	b.sizeLoc = gl.GetUniformLocation(b.program, gl.Str("screenSize\x00"))
+	b.transLoc = gl.GetUniformLocation(b.program, gl.Str("transform\x00"))
 	gl.GenBuffers(1, &b.vbo)
 	gl.BindBuffer(gl.ARRAY_BUFFER, b.vbo)
 	gl.BufferData(gl.ARRAY_BUFFER, vertexSize*cap, nil, gl.STREAM_DRAW)
 	gl.GenVertexArrays(1, &b.vao)
 	gl.BindVertexArray(b.vao)
 	gl.EnableVertexAttribArray(0)
-	gl.VertexAttribPointer(0, 2, gl.INT, false, vertexSize, gl.PtrOffset(0))
+	gl.VertexAttribPointer(0, 2, gl.FLOAT, false, vertexSize, gl.PtrOffset(0))
 	gl.EnableVertexAttribArray(1)
 	gl.VertexAttribPointer(1, 2, gl.FLOAT, false, vertexSize, gl.PtrOffset(8))
 	gl.EnableVertexAttribArray(2)
@@ -42,6 +44,11 @@ func (b *buffer) setScreenSize(w, h int) {
 	gl.Uniform2f(b.sizeLoc, float32(w), float32(h))
 }
 
+func (b *buffer) setTransform(t draw.Transform) {
+	gl.UseProgram(b.program)
+	gl.UniformMatrix3x2fv(b.transLoc, 1, false, &t[0])
+}
+
 func (b *buffer) allocate(n int) []vertex {
 	if n > b.free() {
 		b.flush()
@@ -59,12 +66,14 @@ func (b *buffer) rect(r image.Rectangle, tmin, tmax m.Vec2, color draw.Color) {
 	if r.Empty() {
 		return
 	}
+	min := m.Vec2{float32(r.Min.X), float32(r.Min.Y)}
+	max := m.Vec2{float32(r.Max.X), float32(r.Max.Y)}
 	v := b.allocate(6)
-	v[0] = vertex{[2]int32{int32(r.Min.X), int32(r.Min.Y)}, tmin, color}
-	v[1] = vertex{[2]int32{int32(r.Min.X), int32(r.Max.Y)}, m.Vec2{tmin[0], tmax[1]}, color}
-	v[2] = vertex{[2]int32{int32(r.Max.X), int32(r.Min.Y)}, m.Vec2{tmax[0], tmin[1]}, color}
+	v[0] = vertex{min, tmin, color}
+	v[1] = vertex{m.Vec2{min[0], max[1]}, m.Vec2{tmin[0], tmax[1]}, color}
+	v[2] = vertex{m.Vec2{max[0], min[1]}, m.Vec2{tmax[0], tmin[1]}, color}
 	v[3] = v[2]
-	v[4] = vertex{[2]int32{int32(r.Max.X), int32(r.Max.Y)}, tmax, color}
+	v[4] = vertex{max, tmax, color}
 	v[5] = v[1]
 }
 
@@ -80,8 +89,16 @@ func (b *buffer) flush() {
 	b.buf = b.buf[:0]
 }
 
+// triangle adds an untextured triangle, the colors of the corners are interpolated.
+func (b *buffer) triangle(p0, p1, p2 m.Vec2, c0, c1, c2 draw.Color) {
+	v := b.allocate(3)
+	v[0] = vertex{p0, m.Vec2{}, c0}
+	v[1] = vertex{p1, m.Vec2{}, c1}
+	v[2] = vertex{p2, m.Vec2{}, c2}
+}
+
 type vertex struct {
-	pos   [2]int32
+	pos   m.Vec2
 	tc    m.Vec2
 	color [4]uint8
 }
@@ -149,9 +166,10 @@ out vec2 ftc;
 out vec4 fcol;
 
 uniform vec2 screenSize;
+uniform mat3x2 transform;
 
 void main() {
-	gl_Position = vec4(vec2(-1, 1) + pos/screenSize*vec2(2, -2), 0, 1);
+	gl_Position = vec4(vec2(-1, 1) + transform*vec3(pos, 1)/screenSize*vec2(2, -2), 0, 1);
 	ftc = tc;
 	fcol = color;
 }
@@ -12,10 +12,15 @@ import (
 type Context struct {
 	buffer  buffer
 	sbuffer sbuffer
+	rbuffer rbuffer
 	images  map[*image.RGBA]*entry
 	empty   *Texture
 
 	currentTexture uint32
+	screen         image.Rectangle
+	scissor        image.Rectangle
+	transform      draw.Transform
+	layers         []*layer
 
 	fontContext
 	iconContext
@@ -28,6 +33,7 @@ func (c *Context) Init(f FontLookup) {
 	}
 	c.buffer.init(1024)
 	c.sbuffer.init(512)
+	c.rbuffer.init(512)
 	c.images = make(map[*image.RGBA]*entry)
 	c.empty = NewTextureAlpha(&image.Alpha{Pix: []uint8{255}, Stride: 1, Rect: image.Rect(0, 0, 1, 1)})
 	c.empty.c = c
@@ -46,53 +52,192 @@ func (c *Context) SetIconLookup(l IconLookup) {
 }
 
 func (c *Context) Draw(w, h int, cmd []draw.CommandList) {
+	var target int32
+	gl.GetIntegerv(gl.DRAW_FRAMEBUFFER_BINDING, &target)
 	gl.Viewport(0, 0, int32(w), int32(h))
 	gl.Enable(gl.BLEND)
 	gl.BlendFunc(gl.ONE, gl.ONE_MINUS_SRC_ALPHA)
 	c.buffer.setScreenSize(w, h)
 	c.sbuffer.setScreenSize(w, h)
+	c.rbuffer.setScreenSize(w, h)
 	c.currentTexture = 0
-	for _, l := range cmd {
+	c.screen = draw.WH(w, h)
+	c.scissor = image.Rectangle{}
+	c.transform = draw.Transform{}
+	c.setTransform(draw.Identity)
+	gl.Enable(gl.SCISSOR_TEST)
+	c.setScissor(c.screen)
+	c.drawLists(cmd, uint32(target), 0)
+	c.flush()
+	gl.Disable(gl.SCISSOR_TEST)
+	c.time++
+}
+
+func (c *Context) drawLists(lists []draw.CommandList, target uint32, depth int) {
+	for _, l := range lists {
+		if l.Clip.Empty() {
+			continue
+		}
+		if l.Layer != nil {
+			c.drawLayer(l, target, depth)
+			continue
+		}
+		clip := l.Clip
+		if t := l.Transform; t != draw.Identity && t != (draw.Transform{}) {
+			// transformed commands are clipped to a rectangle that contains the visible area, and then by the scissor test
+			c.setTransform(t)
+			c.setScissor(l.Clip)
+			clip = t.Invert().Bounds(l.Clip)
+		} else {
+			c.setTransform(draw.Identity)
+			// other commands are clipped when they are added to the buffer, but they must not be cut off by the scissor rectangle
+			if !l.Clip.In(c.scissor) {
+				c.setScissor(c.screen)
+			}
+		}
 		for _, cmd := range l.Commands {
 			switch cmd := cmd.(type) {
 			case draw.Fill:
 				c.prepare(c.empty.tex)
-				c.buffer.rect(cmd.Rect.Add(l.Offset).Intersect(l.Clip), m.Vec2{}, m.Vec2{}, cmd.Color)
+				c.buffer.rect(cmd.Rect.Add(l.Offset).Intersect(clip), m.Vec2{}, m.Vec2{}, cmd.Color)
 			case draw.Outline:
 				cmd.Rect = cmd.Rect.Add(l.Offset)
 				r := cmd.Rect
 				c.prepare(c.empty.tex)
 				r.Max.X = r.Min.X + 1
-				c.buffer.rect(r.Intersect(l.Clip), m.Vec2{}, m.Vec2{}, cmd.Color)
+				c.buffer.rect(r.Intersect(clip), m.Vec2{}, m.Vec2{}, cmd.Color)
 				r.Max.X, r.Min.X = cmd.Rect.Max.X, cmd.Rect.Max.X-1
-				c.buffer.rect(r.Intersect(l.Clip), m.Vec2{}, m.Vec2{}, cmd.Color)
+				c.buffer.rect(r.Intersect(clip), m.Vec2{}, m.Vec2{}, cmd.Color)
 				r.Min.X, r.Max.Y = cmd.Rect.Min.X, r.Min.Y+1
-				c.buffer.rect(r.Intersect(l.Clip), m.Vec2{}, m.Vec2{}, cmd.Color)
+				c.buffer.rect(r.Intersect(clip), m.Vec2{}, m.Vec2{}, cmd.Color)
 				r.Max.Y, r.Min.Y = cmd.Rect.Max.Y, cmd.Rect.Max.Y-1
-				c.buffer.rect(r.Intersect(l.Clip), m.Vec2{}, m.Vec2{}, cmd.Color)
+				c.buffer.rect(r.Intersect(clip), m.Vec2{}, m.Vec2{}, cmd.Color)
+			case draw.RoundedFill:
+				c.prepareRounded()
+				c.rbuffer.rect(cmd.Rect.Add(l.Offset), clip, float32(cmd.Radius), 0, solid, [4]float32{}, cmd.Color, cmd.Color)
+			case draw.Border:
+				c.prepareRounded()
+				c.rbuffer.rect(cmd.Rect.Add(l.Offset), clip, float32(cmd.Radius), float32(cmd.Width), solid, [4]float32{}, cmd.Color, cmd.Color)
+			case draw.Gradient:
+				c.prepareRounded()
+				s, e := cmd.Start.Add(l.Offset), cmd.End.Add(l.Offset)
+				mode := linearGradient
+				if cmd.Radial {
+					mode = radialGradient
+				}
+				c.rbuffer.rect(cmd.Rect.Add(l.Offset), clip, float32(cmd.Radius), 0, mode, [4]float32{float32(s.X), float32(s.Y), float32(e.X), float32(e.Y)}, cmd.StartColor, cmd.EndColor)
 			case draw.Text:
 				ff := c.getFontFace(cmd.Font)
-				ff.write(cmd.Text, float32(l.Offset.X+cmd.Position.X), float32(l.Offset.Y+cmd.Position.Y), c, 1, l.Clip, cmd.Color)
+				ff.write(cmd.Text, float32(l.Offset.X+cmd.Position.X), float32(l.Offset.Y+cmd.Position.Y), c, 1, clip, cmd.Color)
 			case draw.Shadow:
 				c.buffer.flush()
-				r := cmd.Rect.Add(l.Offset).Intersect(l.Clip.Inset(cmd.Size))
+				c.rbuffer.flush()
+				r := cmd.Rect.Add(l.Offset).Intersect(clip.Inset(cmd.Size))
 				c.sbuffer.rect(m.Vec2{float32(r.Min.X), float32(r.Min.Y)}, m.Vec2{float32(r.Max.X), float32(r.Max.Y)}, cmd.Rect.Add(l.Offset), float32(cmd.Size), cmd.Color)
 			case draw.Icon:
-				c.drawIcon(cmd.Rect.Add(l.Offset), l.Clip, cmd.Icon, cmd.Color)
+				c.drawIcon(cmd.Rect.Add(l.Offset), clip, cmd.Icon, cmd.Color)
 			case draw.Image:
 				t := c.getImage(cmd.Image, !cmd.Update)
 				c.prepare(t)
-				c.rect(cmd.Rect.Add(l.Offset), l.Clip, [4]float32{0, 0, 1, 1}, cmd.Color)
+				c.rect(cmd.Rect.Add(l.Offset), clip, [4]float32{0, 0, 1, 1}, cmd.Color)
+			case draw.Polyline, draw.Polygon, draw.Ellipse, draw.Arc, draw.Bezier:
+				// vector shapes are clipped by the scissor test
+				c.setScissor(l.Clip)
+				c.prepare(c.empty.tex)
+				c.drawVector(cmd, l.Offset)
 			}
 		}
 	}
+}
+
+// drawLayer draws the lists of a layer into a texture, and then draws the texture with the layer's opacity.
+func (c *Context) drawLayer(l draw.CommandList, target uint32, depth int) {
+	if l.Opacity <= 0 {
+		return
+	}
+	c.flush()
+	t := c.layer(depth)
+	if c.currentTexture == t.tex.tex {
+		c.prepare(c.empty.tex)
+	}
+	gl.BindFramebuffer(gl.FRAMEBUFFER, t.fbo)
+	c.setScissor(l.Clip)
+	transparent := [4]float32{}
+	gl.ClearBufferfv(gl.COLOR, 0, &transparent[0])
+	c.drawLists(l.Layer, t.fbo, depth+1)
+	c.flush()
+	gl.BindFramebuffer(gl.FRAMEBUFFER, target)
+
+	c.setTransform(draw.Identity)
+	c.setScissor(l.Clip)
+	c.prepare(t.tex.tex)
+	a := byte(255)
+	if l.Opacity < 1 {
+		a = byte(l.Opacity * 255)
+	}
+	// the texture is upside down
+	r := l.Clip
+	w, h := float32(c.screen.Dx()), float32(c.screen.Dy())
+	tmin := m.Vec2{float32(r.Min.X) / w, 1 - float32(r.Min.Y)/h}
+	tmax := m.Vec2{float32(r.Max.X) / w, 1 - float32(r.Max.Y)/h}
+	c.buffer.rect(r, tmin, tmax, draw.Color{a, a, a, a})
+}
+
+// layer returns a render target of the size of the screen. Nested layers use different render targets.
+func (c *Context) layer(depth int) *layer {
+	for len(c.layers) <= depth {
+		c.layers = append(c.layers, &layer{})
+	}
+	l := c.layers[depth]
+	w, h := c.screen.Dx(), c.screen.Dy()
+	if l.tex != nil && (l.tex.width != w || l.tex.height != h) {
+		gl.DeleteFramebuffers(1, &l.fbo)
+		gl.DeleteTextures(1, &l.tex.tex)
+		l.tex = nil
+	}
+	if l.tex == nil {
+		l.tex = NewTextureEmpty(w, h)
+		gl.GenFramebuffers(1, &l.fbo)
+		gl.BindFramebuffer(gl.FRAMEBUFFER, l.fbo)
+		gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.TEXTURE_2D, l.tex.tex, 0)
+	}
+	return l
+}
+
+type layer struct {
+	fbo uint32
+	tex *Texture
+}
+
+func (c *Context) setTransform(t draw.Transform) {
+	if t == c.transform {
+		return
+	}
+	c.flush()
+	c.buffer.setTransform(t)
+	c.sbuffer.setTransform(t)
+	c.rbuffer.setTransform(t)
+	c.transform = t
+}
+
+func (c *Context) flush() {
 	c.buffer.flush()
 	c.sbuffer.flush()
-	c.time++
+	c.rbuffer.flush()
+}
+
+func (c *Context) setScissor(r image.Rectangle) {
+	if r == c.scissor {
+		return
+	}
+	c.flush()
+	gl.Scissor(int32(r.Min.X), int32(c.screen.Max.Y-r.Max.Y), int32(r.Dx()), int32(r.Dy()))
+	c.scissor = r
 }
 
 func (c *Context) prepare(t uint32) {
 	c.sbuffer.flush()
+	c.rbuffer.flush()
 	if t != c.currentTexture {
 		c.buffer.flush()
 		gl.BindTexture(gl.TEXTURE_2D, t)
@@ -100,6 +245,11 @@ func (c *Context) prepare(t uint32) {
 	}
 }
 
+func (c *Context) prepareRounded() {
+	c.buffer.flush()
+	c.sbuffer.flush()
+}
+
 func (c *Context) rect(r, clip image.Rectangle, tr [4]float32, color draw.Color) {
 	if clip.Min.X >= r.Max.X {
 		return
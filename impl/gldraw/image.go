@@ -3,6 +3,8 @@ package gldraw
 import (
 	"hash/crc32"
 	"image"
+
+	"github.com/go-gl/gl/v3.3-core/gl"
 )
 
 type entry struct {
@@ -27,6 +29,8 @@ func (c *Context) getImage(i *image.RGBA, static bool) uint32 {
 	}
 	t := NewTexture(i)
 	t.c = c
+	// images are smoothed when they are scaled down, scaled up they show their pixels
+	t.setMinFilter(gl.LINEAR)
 	var ch uint32
 	if !static {
 		ch = checksum(i)
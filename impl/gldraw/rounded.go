@@ -0,0 +1,184 @@
+package gldraw
+
+import (
+	"image"
+
+	"github.com/jfreymuth/ui/draw"
+
+	"github.com/go-gl/gl/v3.3-core/gl"
+	m "github.com/go-gl/mathgl/mgl32"
+)
+
+// rbuffer draws rounded rectangles, borders and gradients.
+type rbuffer struct {
+	vao, vbo uint32
+	buf      []rvertex
+	program  uint32
+	sizeLoc  int32
+	transLoc int32
+}
+
+const (
+	solid = iota
+	linearGradient
+	radialGradient
+)
+
+func (b *rbuffer) init(cap int) {
+	b.buf = make([]rvertex, 0, cap)
+	b.program = createProgram(rvss, rfss)
+	gl.UseProgram(b.program)
+	b.sizeLoc = gl.GetUniformLocation(b.program, gl.Str("screenSize\x00"))
+	b.transLoc = gl.GetUniformLocation(b.program, gl.Str("transform\x00"))
+	gl.GenBuffers(1, &b.vbo)
+	gl.BindBuffer(gl.ARRAY_BUFFER, b.vbo)
+	gl.BufferData(gl.ARRAY_BUFFER, rvertexSize*cap, nil, gl.STREAM_DRAW)
+	gl.GenVertexArrays(1, &b.vao)
+	gl.BindVertexArray(b.vao)
+	gl.EnableVertexAttribArray(0)
+	gl.VertexAttribPointer(0, 2, gl.FLOAT, false, rvertexSize, gl.PtrOffset(0))
+	gl.EnableVertexAttribArray(1)
+	gl.VertexAttribPointer(1, 4, gl.FLOAT, false, rvertexSize, gl.PtrOffset(8))
+	gl.EnableVertexAttribArray(2)
+	gl.VertexAttribPointer(2, 4, gl.FLOAT, false, rvertexSize, gl.PtrOffset(24))
+	gl.EnableVertexAttribArray(3)
+	gl.VertexAttribPointer(3, 4, gl.FLOAT, false, rvertexSize, gl.PtrOffset(40))
+	gl.EnableVertexAttribArray(4)
+	gl.VertexAttribPointer(4, 4, gl.UNSIGNED_BYTE, true, rvertexSize, gl.PtrOffset(56))
+	gl.EnableVertexAttribArray(5)
+	gl.VertexAttribPointer(5, 4, gl.UNSIGNED_BYTE, true, rvertexSize, gl.PtrOffset(60))
+}
+
+func (b *rbuffer) setScreenSize(w, h int) {
+	gl.UseProgram(b.program)
+	gl.Uniform2f(b.sizeLoc, float32(w), float32(h))
+}
+
+func (b *rbuffer) setTransform(t draw.Transform) {
+	gl.UseProgram(b.program)
+	gl.UniformMatrix3x2fv(b.transLoc, 1, false, &t[0])
+}
+
+func (b *rbuffer) allocate(n int) []rvertex {
+	if n > b.free() {
+		b.flush()
+	}
+	i := len(b.buf)
+	b.buf = b.buf[:i+n]
+	return b.buf[i : i+n]
+}
+
+func (b *rbuffer) free() int {
+	return cap(b.buf) - len(b.buf)
+}
+
+// rect draws the part of rect that is inside clip. If width is greater than 0, only a border of that width is drawn.
+// mode is solid, linearGradient or radialGradient, grad contains the start and end point of a gradient.
+func (b *rbuffer) rect(rect, clip image.Rectangle, radius, width float32, mode int, grad [4]float32, c0, c1 draw.Color) {
+	r := rect.Intersect(clip)
+	if r.Empty() {
+		return
+	}
+	rect32 := [4]float32{float32(rect.Min.X), float32(rect.Min.Y), float32(rect.Max.X), float32(rect.Max.Y)}
+	params := [4]float32{radius, width, float32(mode), 0}
+	min := m.Vec2{float32(r.Min.X), float32(r.Min.Y)}
+	max := m.Vec2{float32(r.Max.X), float32(r.Max.Y)}
+	v := b.allocate(6)
+	v[0] = rvertex{min, rect32, params, grad, c0, c1}
+	v[1] = rvertex{m.Vec2{min[0], max[1]}, rect32, params, grad, c0, c1}
+	v[2] = rvertex{m.Vec2{max[0], min[1]}, rect32, params, grad, c0, c1}
+	v[3] = v[2]
+	v[4] = rvertex{max, rect32, params, grad, c0, c1}
+	v[5] = v[1]
+}
+
+func (b *rbuffer) flush() {
+	if len(b.buf) == 0 {
+		return
+	}
+	gl.UseProgram(b.program)
+	gl.BindBuffer(gl.ARRAY_BUFFER, b.vbo)
+	gl.BufferSubData(gl.ARRAY_BUFFER, 0, len(b.buf)*rvertexSize, gl.Ptr(b.buf))
+	gl.BindVertexArray(b.vao)
+	gl.DrawArrays(gl.TRIANGLES, 0, int32(len(b.buf)))
+	b.buf = b.buf[:0]
+}
+
+type rvertex struct {
+	pos    m.Vec2
+	rect   [4]float32
+	params [4]float32
+	grad   [4]float32
+	color0 [4]uint8
+	color1 [4]uint8
+}
+
+const rvertexSize = 64
+
+var rvss = `#version 330
+layout(location = 0) in vec2 pos;
+layout(location = 1) in vec4 rect;
+layout(location = 2) in vec4 params;
+layout(location = 3) in vec4 grad;
+layout(location = 4) in vec4 color0;
+layout(location = 5) in vec4 color1;
+
+out vec2 fpos;
+out vec4 frect;
+out vec4 fparams;
+out vec4 fgrad;
+out vec4 fcol0;
+out vec4 fcol1;
+
+uniform vec2 screenSize;
+uniform mat3x2 transform;
+
+void main() {
+	gl_Position = vec4(vec2(-1, 1) + transform*vec3(pos, 1)/screenSize*vec2(2, -2), 0, 1);
+	fpos = pos;
+	frect = rect;
+	fparams = params;
+	fgrad = grad;
+	fcol0 = color0;
+	fcol1 = color1;
+}
+` + "\x00"
+
+var rfss = `#version 330
+in vec2 fpos;
+in vec4 frect;
+in vec4 fparams;
+in vec4 fgrad;
+in vec4 fcol0;
+in vec4 fcol1;
+
+out vec4 outcol;
+
+// the signed distance from a point to a rectangle with rounded corners
+float roundedBox(vec2 p, vec4 rect, float r) {
+	vec2 center = (rect.xy + rect.zw) * 0.5;
+	vec2 size = (rect.zw - rect.xy) * 0.5;
+	r = max(min(r, min(size.x, size.y)), 0.0);
+	vec2 q = abs(p - center) - size + r;
+	return length(max(q, 0.0)) + min(max(q.x, q.y), 0.0) - r;
+}
+
+void main() {
+	float radius = fparams.x, width = fparams.y;
+	float a = clamp(0.5 - roundedBox(fpos, frect, radius), 0.0, 1.0);
+	if (width > 0.0) {
+		vec4 inner = frect + vec4(width, width, -width, -width);
+		a *= clamp(0.5 + roundedBox(fpos, inner, radius - width), 0.0, 1.0);
+	}
+	vec4 col = fcol0;
+	if (fparams.z > 1.5) {
+		float t = length(fpos - fgrad.xy) / max(length(fgrad.zw - fgrad.xy), 1e-6);
+		col = mix(fcol0, fcol1, clamp(t, 0.0, 1.0));
+	} else if (fparams.z > 0.5) {
+		vec2 d = fgrad.zw - fgrad.xy;
+		float t = dot(fpos - fgrad.xy, d) / max(dot(d, d), 1e-6);
+		col = mix(fcol0, fcol1, clamp(t, 0.0, 1.0));
+	}
+	outcol = col * a;
+}
+` + "\x00"
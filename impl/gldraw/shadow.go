@@ -14,6 +14,7 @@ type sbuffer struct {
 	buf      []svertex
 	program  uint32
 	sizeLoc  int32
+	transLoc int32
 }
 
 func (b *sbuffer) init(cap int) {
@@ -21,6 +22,7 @@ func (b *sbuffer) init(cap int) {
 	b.program = createProgram(svss, sfss)
 	gl.UseProgram(b.program)
 	b.sizeLoc = gl.GetUniformLocation(b.program, gl.Str("screenSize\x00"))
+	b.transLoc = gl.GetUniformLocation(b.program, gl.Str("transform\x00"))
 	gl.GenBuffers(1, &b.vbo)
 	gl.BindBuffer(gl.ARRAY_BUFFER, b.vbo)
 	gl.BufferData(gl.ARRAY_BUFFER, svertexSize*cap, nil, gl.STREAM_DRAW)
@@ -39,6 +41,11 @@ func (b *sbuffer) setScreenSize(w, h int) {
 	gl.Uniform2f(b.sizeLoc, float32(w), float32(h))
 }
 
+func (b *sbuffer) setTransform(t draw.Transform) {
+	gl.UseProgram(b.program)
+	gl.UniformMatrix3x2fv(b.transLoc, 1, false, &t[0])
+}
+
 func (b *sbuffer) allocate(n int) []svertex {
 	if n > b.free() {
 		b.flush()
@@ -97,9 +104,10 @@ out vec4 frect;
 out float fr;
 
 uniform vec2 screenSize;
+uniform mat3x2 transform;
 
 void main() {
-	gl_Position = vec4(vec2(-1, 1) + pos.xy/screenSize*vec2(2, -2), 0, 1);
+	gl_Position = vec4(vec2(-1, 1) + transform*vec3(pos.xy, 1)/screenSize*vec2(2, -2), 0, 1);
 	fpos = pos.xy;
 	fr = pos.z;
 	frect = rect;
@@ -32,6 +32,14 @@ func (t *Texture) initBase(w, h int) {
 	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
 }
 
+func (t *Texture) setMinFilter(filter int32) {
+	var old int32
+	gl.GetIntegerv(gl.TEXTURE_BINDING_2D, &old)
+	gl.BindTexture(gl.TEXTURE_2D, t.tex)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, filter)
+	gl.BindTexture(gl.TEXTURE_2D, uint32(old))
+}
+
 func (t *Texture) initBaseAlpha(w, h int) {
 	t.alpha = true
 	t.initBase(w, h)
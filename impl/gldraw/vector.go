@@ -0,0 +1,326 @@
+package gldraw
+
+import (
+	"image"
+	"math"
+
+	"github.com/jfreymuth/ui/draw"
+
+	m "github.com/go-gl/mathgl/mgl32"
+)
+
+// Curves are approximated by line segments that deviate from the curve by at most this many pixels.
+const tolerance = .25
+
+// Sharper corners of strokes are beveled instead of mitered.
+const miterLimit = 4
+
+// drawVector tessellates a vector command into triangles.
+// Edges are antialiased by a one pixel wide fringe that fades to transparent.
+func (c *Context) drawVector(cmd draw.Command, offset image.Point) {
+	o := m.Vec2{float32(offset.X), float32(offset.Y)}
+	b := &c.buffer
+	switch cmd := cmd.(type) {
+	case draw.Polyline:
+		stroke(b, toVec(cmd.Points, o), cmd.Width, cmd.Closed, cmd.Color)
+	case draw.Polygon:
+		fill(b, toVec(cmd.Points, o), cmd.Color)
+	case draw.Ellipse:
+		center := toVec([]draw.Point{cmd.Center}, o)[0]
+		if cmd.Width > 0 {
+			stroke(b, arcPoints(center, cmd.RX, cmd.RY, 0, 2*math.Pi, cmd.Width/2), cmd.Width, true, cmd.Color)
+		} else {
+			fill(b, arcPoints(center, cmd.RX, cmd.RY, 0, 2*math.Pi, 0), cmd.Color)
+		}
+	case draw.Arc:
+		center := toVec([]draw.Point{cmd.Center}, o)[0]
+		if cmd.Width > 0 {
+			stroke(b, arcPoints(center, cmd.RX, cmd.RY, cmd.Start, cmd.End, cmd.Width/2), cmd.Width, false, cmd.Color)
+		} else if math.Abs(float64(cmd.End-cmd.Start)) >= 2*math.Pi {
+			fill(b, arcPoints(center, cmd.RX, cmd.RY, 0, 2*math.Pi, 0), cmd.Color)
+		} else {
+			fill(b, append([]m.Vec2{center}, arcPoints(center, cmd.RX, cmd.RY, cmd.Start, cmd.End, 0)...), cmd.Color)
+		}
+	case draw.Bezier:
+		p := bezierPoints(toVec(cmd.Points, o))
+		if cmd.Width > 0 {
+			// a path that ends where it starts is drawn without a gap
+			closed := len(p) > 2 && p[0].ApproxEqualThreshold(p[len(p)-1], 1e-3)
+			stroke(b, p, cmd.Width, closed, cmd.Color)
+		} else {
+			fill(b, p, cmd.Color)
+		}
+	}
+}
+
+func toVec(p []draw.Point, offset m.Vec2) []m.Vec2 {
+	v := make([]m.Vec2, len(p))
+	for i, p := range p {
+		v[i] = m.Vec2{p.X, p.Y}.Add(offset)
+	}
+	return v
+}
+
+// arcPoints approximates a part of an ellipse. extra is added to the radius when calculating the number of segments.
+func arcPoints(center m.Vec2, rx, ry, start, end, extra float32) []m.Vec2 {
+	rx, ry = abs(rx), abs(ry)
+	if end-start > 2*math.Pi {
+		end = start + 2*math.Pi
+	} else if start-end > 2*math.Pi {
+		end = start - 2*math.Pi
+	}
+	r := rx
+	if ry > r {
+		r = ry
+	}
+	r += extra
+	step := 2 * math.Pi / 8
+	if r > tolerance {
+		step = math.Min(step, 2*math.Acos(1-tolerance/float64(r)))
+	}
+	n := int(math.Ceil(math.Abs(float64(end-start)) / step))
+	if n < 1 {
+		n = 1
+	} else if n > 1000 {
+		n = 1000
+	}
+	full := abs(end-start) >= 2*math.Pi
+	p := make([]m.Vec2, 0, n+1)
+	for i := 0; i <= n; i++ {
+		if i == n && full {
+			break
+		}
+		a := float64(start) + float64(end-start)*float64(i)/float64(n)
+		p = append(p, m.Vec2{center[0] + rx*float32(math.Cos(a)), center[1] + ry*float32(math.Sin(a))})
+	}
+	return p
+}
+
+// bezierPoints approximates a path of cubic Bézier curves.
+func bezierPoints(c []m.Vec2) []m.Vec2 {
+	p := []m.Vec2{c[0]}
+	for ; len(c) >= 4; c = c[3:] {
+		p0, p1, p2, p3 := c[0], c[1], c[2], c[3]
+		// the number of segments needed to stay within the tolerance (Wang's formula)
+		d := p0.Sub(p1.Mul(2)).Add(p2).Len()
+		if d2 := p1.Sub(p2.Mul(2)).Add(p3).Len(); d2 > d {
+			d = d2
+		}
+		n := int(math.Ceil(math.Sqrt(float64(.75 * d / tolerance))))
+		if n < 1 {
+			n = 1
+		} else if n > 256 {
+			n = 256
+		}
+		for i := 1; i <= n; i++ {
+			t := float32(i) / float32(n)
+			u := 1 - t
+			p = append(p, p0.Mul(u*u*u).Add(p1.Mul(3*u*u*t)).Add(p2.Mul(3*u*t*t)).Add(p3.Mul(t*t*t)))
+		}
+	}
+	return p
+}
+
+// clean removes consecutive duplicate points, and the last point if it is equal to the first one.
+func clean(p []m.Vec2, closed bool) []m.Vec2 {
+	out := make([]m.Vec2, 0, len(p))
+	for _, v := range p {
+		if len(out) == 0 || !v.ApproxEqualThreshold(out[len(out)-1], 1e-3) {
+			out = append(out, v)
+		}
+	}
+	if closed && len(out) > 1 && out[0].ApproxEqualThreshold(out[len(out)-1], 1e-3) {
+		out = out[:len(out)-1]
+	}
+	return out
+}
+
+// normal returns the unit normal of the line from p to q, it points to the left when y points down.
+func normal(p, q m.Vec2) m.Vec2 {
+	d := q.Sub(p).Normalize()
+	return m.Vec2{d[1], -d[0]}
+}
+
+// miter returns the offset of a corner between two edges with the normals n0 and n1.
+// ok is false if the corner is too sharp.
+func miter(n0, n1 m.Vec2) (off m.Vec2, ok bool) {
+	d := 1 + n0.Dot(n1)
+	// the length of the offset is sqrt(2/d)
+	if d < 2/(miterLimit*miterLimit) {
+		return n0, false
+	}
+	return n0.Add(n1).Mul(1 / d), true
+}
+
+type section struct {
+	p, n m.Vec2
+	cap  bool
+}
+
+// stroke draws a line of the given width along the points.
+func stroke(b *buffer, p []m.Vec2, width float32, closed bool, color draw.Color) {
+	p = clean(p, closed)
+	if len(p) < 2 {
+		return
+	}
+	if len(p) == 2 {
+		closed = false
+	}
+	// lines thinner than a pixel are drawn one pixel wide, with reduced alpha
+	core, outer := width/2-.5, width/2+.5
+	if width < 1 {
+		core, outer = 0, 1
+		for i := range color {
+			color[i] = uint8(float32(color[i]) * width)
+		}
+	}
+
+	n := len(p)
+	s := make([]section, 0, n+4)
+	for i := 0; i < n; i++ {
+		switch {
+		case !closed && i == 0:
+			d := p[1].Sub(p[0]).Normalize().Mul(.5)
+			nv := normal(p[0], p[1])
+			s = append(s, section{p[0].Sub(d), nv, true}, section{p[0].Add(d), nv, false})
+		case !closed && i == n-1:
+			d := p[i].Sub(p[i-1]).Normalize().Mul(.5)
+			nv := normal(p[i-1], p[i])
+			s = append(s, section{p[i].Sub(d), nv, false}, section{p[i].Add(d), nv, true})
+		default:
+			prev, next := p[(i+n-1)%n], p[(i+1)%n]
+			n0, n1 := normal(prev, p[i]), normal(p[i], next)
+			if off, ok := miter(n0, n1); ok {
+				s = append(s, section{p[i], off, false})
+			} else {
+				s = append(s, section{p[i], n0, false}, section{p[i], n1, false})
+			}
+		}
+	}
+	if closed {
+		s = append(s, s[0])
+	}
+
+	offsets := [4]float32{outer, core, -core, -outer}
+	colors := [4]draw.Color{{}, color, color, {}}
+	for i := 1; i < len(s); i++ {
+		a, z := s[i-1], s[i]
+		for j := 0; j < 3; j++ {
+			a0, a1 := a.p.Add(a.n.Mul(offsets[j])), a.p.Add(a.n.Mul(offsets[j+1]))
+			z0, z1 := z.p.Add(z.n.Mul(offsets[j])), z.p.Add(z.n.Mul(offsets[j+1]))
+			ca0, ca1, cz0, cz1 := colors[j], colors[j+1], colors[j], colors[j+1]
+			if a.cap {
+				ca0, ca1 = draw.Color{}, draw.Color{}
+			}
+			if z.cap {
+				cz0, cz1 = draw.Color{}, draw.Color{}
+			}
+			b.triangle(a0, a1, z0, ca0, ca1, cz0)
+			b.triangle(z0, a1, z1, cz0, ca1, cz1)
+		}
+	}
+}
+
+// fill fills a polygon. The edges are antialiased by fading from half a pixel inside to half a pixel outside.
+func fill(b *buffer, p []m.Vec2, color draw.Color) {
+	p = clean(p, true)
+	if len(p) < 3 {
+		return
+	}
+	n := len(p)
+	area := float32(0)
+	for i := range p {
+		j := (i + 1) % n
+		area += p[i][0]*p[j][1] - p[j][0]*p[i][1]
+	}
+	if area == 0 {
+		return
+	}
+	// the offsets point outwards
+	inner, outer := make([]m.Vec2, n), make([]m.Vec2, n)
+	for i := range p {
+		prev, next := p[(i+n-1)%n], p[(i+1)%n]
+		n0, n1 := normal(prev, p[i]), normal(p[i], next)
+		if area < 0 {
+			n0, n1 = n0.Mul(-1), n1.Mul(-1)
+		}
+		off, ok := miter(n0, n1)
+		if l := n0.Add(n1).Len(); !ok && l > 1e-3 {
+			off = n0.Add(n1).Mul(miterLimit / l)
+		}
+		inner[i], outer[i] = p[i].Sub(off.Mul(.5)), p[i].Add(off.Mul(.5))
+	}
+	for _, t := range triangulate(p, area > 0) {
+		b.triangle(inner[t[0]], inner[t[1]], inner[t[2]], color, color, color)
+	}
+	for i := range p {
+		j := (i + 1) % n
+		b.triangle(inner[i], outer[i], inner[j], color, draw.Color{}, color)
+		b.triangle(inner[j], outer[i], outer[j], color, draw.Color{}, draw.Color{})
+	}
+}
+
+// triangulate splits a simple polygon into triangles by ear clipping.
+// If the polygon intersects itself, the remaining part is drawn as a triangle fan.
+func triangulate(p []m.Vec2, positive bool) [][3]int {
+	n := len(p)
+	tris := make([][3]int, 0, n-2)
+	cross := func(a, b, c m.Vec2) float32 {
+		x := (b[0]-a[0])*(c[1]-b[1]) - (b[1]-a[1])*(c[0]-b[0])
+		if !positive {
+			return -x
+		}
+		return x
+	}
+	convex := true
+	for i := range p {
+		if cross(p[(i+n-1)%n], p[i], p[(i+1)%n]) < 0 {
+			convex = false
+			break
+		}
+	}
+	idx := make([]int, n)
+	for i := range idx {
+		idx[i] = i
+	}
+	if !convex {
+		for i, fails := 0, 0; len(idx) > 3 && fails < len(idx); {
+			i %= len(idx)
+			a, b, c := idx[(i+len(idx)-1)%len(idx)], idx[i], idx[(i+1)%len(idx)]
+			if isEar(p, idx, a, b, c, cross) {
+				tris = append(tris, [3]int{a, b, c})
+				idx = append(idx[:i], idx[i+1:]...)
+				fails = 0
+			} else {
+				i++
+				fails++
+			}
+		}
+	}
+	for i := 2; i < len(idx); i++ {
+		tris = append(tris, [3]int{idx[0], idx[i-1], idx[i]})
+	}
+	return tris
+}
+
+func isEar(p []m.Vec2, idx []int, a, b, c int, cross func(a, b, c m.Vec2) float32) bool {
+	if cross(p[a], p[b], p[c]) <= 0 {
+		return false
+	}
+	for _, i := range idx {
+		if i == a || i == b || i == c {
+			continue
+		}
+		if cross(p[a], p[b], p[i]) >= 0 && cross(p[b], p[c], p[i]) >= 0 && cross(p[c], p[a], p[i]) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func abs(f float32) float32 {
+	if f < 0 {
+		return -f
+	}
+	return f
+}
@@ -51,6 +51,8 @@ var iconData = map[string][]byte{
 	"right.arrow": icons.NavigationArrowForward,
 	"zoomIn":      icons.ActionZoomIn,
 	"zoomOut":     icons.ActionZoomOut,
+	"zoomFit":     icons.ImageCropFree,
+	"zoomActual":  icons.ImageCropOriginal,
 
 	"add":              icons.ContentAdd,
 	"add.file":         icons.ActionNoteAdd,
@@ -70,6 +72,11 @@ var iconData = map[string][]byte{
 	"file.exec":    icons.ActionSettingsApplications,
 	"file.archive": icons.FileFolder,
 
+	"home":         icons.ActionHome,
+	"history":      icons.ActionHistory,
+	"bookmark":     icons.ActionBookmark,
+	"bookmark.add": icons.ActionBookmarkBorder,
+
 	"info":     icons.ActionInfo,
 	"question": icons.ActionHelp,
 	"error":    icons.AlertError,
@@ -94,6 +101,9 @@ var iconData = map[string][]byte{
 	"search":  icons.ActionSearch,
 	"refresh": icons.NavigationRefresh,
 
+	"view.list":    icons.ActionViewList,
+	"view.details": icons.ActionViewHeadline,
+
 	"menu":     icons.NavigationMenu,
 	"settings": icons.ActionSettings,
 	"close":    icons.NavigationClose,
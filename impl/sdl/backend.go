@@ -105,6 +105,7 @@ func Show(opt Options) {
 	cursorCache := make(map[ui.Cursor]*sdl.Cursor)
 	clipboard, _ := sdl.GetClipboardText()
 	state.SetClipboardString(clipboard)
+	state.SetWaker(func() { sdl.PushEvent(&sdl.UserEvent{Type: sdl.USEREVENT, Code: 4}) })
 
 	go func() {
 		for {
@@ -177,6 +178,8 @@ func Show(opt Options) {
 					state.SetBlink(false)
 				case 3:
 					(<-funcs)(&state.State)
+				case 4:
+					// sent by the waker, the window is updated after all events are handled
 				}
 			default:
 			}
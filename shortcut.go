@@ -0,0 +1,119 @@
+package ui
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// A Shortcut is a key that is pressed while holding some modifiers.
+type Shortcut struct {
+	Key       Key
+	Modifiers Modifier
+}
+
+var modifierNames = []struct {
+	mod  Modifier
+	name string
+}{
+	{Control, "Ctrl"},
+	{Alt, "Alt"},
+	{Shift, "Shift"},
+	{Super, "Super"},
+}
+
+var keyNames = map[Key]string{
+	KeyEnter: "Enter", KeyEscape: "Escape", KeyBackspace: "Backspace", KeyTab: "Tab", KeySpace: "Space",
+	KeyF1: "F1", KeyF2: "F2", KeyF3: "F3", KeyF4: "F4", KeyF5: "F5", KeyF6: "F6",
+	KeyF7: "F7", KeyF8: "F8", KeyF9: "F9", KeyF10: "F10", KeyF11: "F11", KeyF12: "F12",
+	KeyPrintScreen: "PrintScreen", KeyScrollLock: "ScrollLock", KeyPause: "Pause",
+	KeyInsert: "Insert", KeyHome: "Home", KeyPageUp: "PageUp", KeyDelete: "Delete", KeyEnd: "End", KeyPageDown: "PageDown",
+	KeyRight: "Right", KeyLeft: "Left", KeyDown: "Down", KeyUp: "Up",
+	KeyNumpadDiv: "Numpad/", KeyNumpadMul: "Numpad*", KeyNumpadMinus: "Numpad-", KeyNumpadPlus: "Numpad+",
+	KeyNumpadEnter: "NumpadEnter", KeyNumpadDot: "Numpad.", KeyMenu: "Menu", KeyCapsLock: "CapsLock", KeyNumLock: "NumLock",
+}
+
+// String returns the name of a key, e.g. "A", "F5" or "PageUp".
+func (k Key) String() string {
+	switch {
+	case k >= KeyA && k <= KeyZ:
+		return string(rune('A' + k - KeyA))
+	case k >= Key1 && k <= Key9:
+		return string(rune('1' + k - Key1))
+	case k == Key0:
+		return "0"
+	case k >= KeyNumpad1 && k <= KeyNumpad9:
+		return "Numpad" + string(rune('1'+k-KeyNumpad1))
+	case k == KeyNumpad0:
+		return "Numpad0"
+	}
+	if name, ok := keyNames[k]; ok {
+		return name
+	}
+	return "Key" + strconv.Itoa(int(k))
+}
+
+// String returns a string like "Ctrl+Shift+S", that can be parsed by ParseShortcut.
+func (s Shortcut) String() string {
+	var b strings.Builder
+	for _, m := range modifierNames {
+		if s.Modifiers&m.mod != 0 {
+			b.WriteString(m.name)
+			b.WriteByte('+')
+		}
+	}
+	b.WriteString(s.Key.String())
+	return b.String()
+}
+
+// ParseShortcut parses a string like "Ctrl+Shift+S".
+// Modifier and key names are not case sensitive, "Control", "Cmd" and "Meta" are accepted as alternative names.
+func ParseShortcut(s string) (Shortcut, error) {
+	var sc Shortcut
+	parts := strings.Split(s, "+")
+	// "Numpad+" and "Ctrl+Numpad+" end with an empty part
+	for i := len(parts) - 1; i > 0; i-- {
+		if parts[i] == "" {
+			parts[i-1] += "+"
+			parts = append(parts[:i], parts[i+1:]...)
+		}
+	}
+	for _, p := range parts[:len(parts)-1] {
+		switch strings.ToLower(strings.TrimSpace(p)) {
+		case "ctrl", "control":
+			sc.Modifiers |= Control
+		case "alt":
+			sc.Modifiers |= Alt
+		case "shift":
+			sc.Modifiers |= Shift
+		case "super", "cmd", "meta":
+			sc.Modifiers |= Super
+		default:
+			return Shortcut{}, errors.New("ui: unknown modifier \"" + p + "\" in shortcut \"" + s + "\"")
+		}
+	}
+	sc.Key = parseKey(strings.TrimSpace(parts[len(parts)-1]))
+	if sc.Key == KeyUnknown {
+		return Shortcut{}, errors.New("ui: unknown key in shortcut \"" + s + "\"")
+	}
+	return sc, nil
+}
+
+func parseKey(name string) Key {
+	for k := KeyA; k <= KeyMenu; k++ {
+		if strings.EqualFold(name, k.String()) {
+			return k
+		}
+	}
+	switch strings.ToLower(name) {
+	case "esc":
+		return KeyEscape
+	case "return":
+		return KeyEnter
+	case "del":
+		return KeyDelete
+	case "ins":
+		return KeyInsert
+	}
+	return KeyUnknown
+}
@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestShortcutRoundTrip(t *testing.T) {
+	for k := KeyA; k <= KeyMenu; k++ {
+		if k.String() == "Key"+strconv.Itoa(int(k)) {
+			// keys without a name can not be parsed
+			continue
+		}
+		for _, mod := range []Modifier{0, Control, Alt | Shift, Control | Alt | Shift | Super} {
+			sc := Shortcut{k, mod}
+			parsed, err := ParseShortcut(sc.String())
+			if err != nil {
+				t.Errorf("%v: %v", sc, err)
+			} else if parsed != sc {
+				t.Errorf("ParseShortcut(%q) = %v, want %v", sc.String(), parsed, sc)
+			}
+		}
+	}
+}
+
+func TestParseShortcut(t *testing.T) {
+	tests := []struct {
+		s    string
+		want Shortcut
+	}{
+		{"Ctrl+S", Shortcut{KeyS, Control}},
+		{"ctrl+shift+s", Shortcut{KeyS, Control | Shift}},
+		{"Control + Alt + Delete", Shortcut{KeyDelete, Control | Alt}},
+		{"Cmd+Q", Shortcut{KeyQ, Super}},
+		{"Meta+Esc", Shortcut{KeyEscape, Super}},
+		{"F5", Shortcut{KeyF5, 0}},
+		{"Numpad+", Shortcut{KeyNumpadPlus, 0}},
+		{"Ctrl+Numpad+", Shortcut{KeyNumpadPlus, Control}},
+		{"Ctrl+Numpad-", Shortcut{KeyNumpadMinus, Control}},
+		{"Alt+Return", Shortcut{KeyEnter, Alt}},
+		{"Shift+0", Shortcut{Key0, Shift}},
+	}
+	for _, tt := range tests {
+		got, err := ParseShortcut(tt.s)
+		if err != nil {
+			t.Errorf("ParseShortcut(%q): %v", tt.s, err)
+		} else if got != tt.want {
+			t.Errorf("ParseShortcut(%q) = %v, want %v", tt.s, got, tt.want)
+		}
+	}
+	for _, s := range []string{"", "Ctrl+", "Hyper+A", "Ctrl+Foo", "Ctrl++"} {
+		if sc, err := ParseShortcut(s); err == nil {
+			t.Errorf("ParseShortcut(%q) = %v, want an error", s, sc)
+		}
+	}
+}
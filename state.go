@@ -22,20 +22,30 @@ type State struct {
 	drag          interface{} // drag and drop, content
 	drop          bool        // drag and drop, mouse just released
 	focused       Component   // keyboard focus
+	focusPath     []Component // ancestors of the focused component
+	dialogFocus   []Component // keyboard focus when each open dialog was opened
+	path          []Component // ancestors of the current component
 	focusNext     bool
 	lastFocusable Component
 	mouseButtons  MouseButton // pressed mouse buttons
 	clickButtons  MouseButton // mouse buttons released since last update
+	lastButtons   MouseButton // mouse buttons pressed during the last update
 	clicks        int         // number of mouse clicks
 	modifiers     Modifier
 	scroll        image.Point // mouse wheel input
 	textInput     string
 	keyPresses    []Key
+	hotKeys       map[Shortcut][]Component // hot keys registered during the current update
+	lastHotKeys   map[Shortcut][]Component // hot keys registered during the last update
+	hotKey        Shortcut
+	hotKeyTarget  Component
+	hotKeyCount   int // how often hotKey was pressed, used to cycle through components sharing it
 	root          Root
 	cursor        Cursor
 	windowTitle   string
 	windowSize    image.Rectangle // always at (0,0)
 	clipboard     string
+	wake          func() // requests an update from another goroutine, set by the backend
 	time          float32
 	blink         bool
 
@@ -94,6 +104,21 @@ func (s *State) KeyboardFocus() Component {
 	return s.focused
 }
 
+// HasKeyboardFocusWithin returns true if c or one of its descendants receives keyboard events.
+// The ancestors of the focused component are recorded when it is updated,
+// so changes to the focus are only taken into account after the focused component has been updated.
+func (s *State) HasKeyboardFocusWithin(c Component) bool {
+	if s.focused == c {
+		return true
+	}
+	for _, p := range s.focusPath {
+		if p == c {
+			return true
+		}
+	}
+	return false
+}
+
 // HasKeyboardFocus returns true if the current component receives keyboard events.
 func (s *State) HasKeyboardFocus() bool {
 	if s.disabled {
@@ -131,11 +156,22 @@ func (s *State) MouseButtonDown(b MouseButton) bool {
 	return s.HasMouseFocus() && s.mouseButtons&b == b
 }
 
+// MouseButtonDownWithin returns true if a given mouse button is pressed while the cursor is inside the current component.
+// Unlike MouseButtonDown, this also returns true if the mouse is grabbed by one of the component's descendants.
+func (s *State) MouseButtonDownWithin(b MouseButton) bool {
+	return !s.disabled && s.drag == nil && s.hovered && s.mouseButtons&b == b
+}
+
 // MouseClick returns true if a given mouse button was clicked between the current and last update.
 func (s *State) MouseClick(b MouseButton) bool {
 	return s.HasMouseFocus() && s.clickButtons&b != 0
 }
 
+// MousePress returns true if a given mouse button was pressed between the current and last update.
+func (s *State) MousePress(b MouseButton) bool {
+	return s.HasMouseFocus() && s.mouseButtons&^s.lastButtons&b != 0
+}
+
 // ClickCount returns the number of consecutive mouse clicks.
 func (s *State) ClickCount() int {
 	return s.clicks
@@ -146,6 +182,12 @@ func (s *State) HasModifiers(m Modifier) bool {
 	return s.modifiers&m == m
 }
 
+// HasExactModifiers returns true if the given modifiers, and no other modifiers are currently active.
+// CapsLock and NumLock are ignored.
+func (s *State) HasExactModifiers(m Modifier) bool {
+	return s.modifiers&^(CapsLock|NumLock) == m&^(CapsLock|NumLock)
+}
+
 // MousePos returns the position of the cursor relative to the current component.
 func (s *State) MousePos() image.Point {
 	return s.mousePos.Sub(s.bounds.Min)
@@ -183,6 +225,83 @@ func (s *State) PeekKeyPresses() []Key {
 	return s.keyPresses
 }
 
+// ConsumeKeyPress removes a key event, so that it will not be delivered to any component.
+func (s *State) ConsumeKeyPress(k Key) {
+	for i, p := range s.keyPresses {
+		if p == k {
+			s.keyPresses = append(s.keyPresses[:i], s.keyPresses[i+1:]...)
+			return
+		}
+	}
+}
+
+// IgnoreKeyPress gives back a key event returned by KeyPresses that the component did not handle.
+// The event can then be seen with PeekKeyPresses by code that runs later, e.g. the keyboard shortcuts of the root component.
+func (s *State) IgnoreKeyPress(k Key) {
+	s.keyPresses = append(s.keyPresses, k)
+}
+
+// HotKey registers a key combination that activates the current component, even if it does not have keyboard focus.
+// It returns true if the key combination was pressed, the key event will not be delivered to other components.
+// Hot keys are only active while the component that registered them is updated.
+// If several components register the same hot key, repeated presses activate them in turn.
+func (s *State) HotKey(sc Shortcut) bool {
+	if s.disabled {
+		return false
+	}
+	if s.hotKeys == nil {
+		s.hotKeys = make(map[Shortcut][]Component)
+	}
+	if !containsComponent(s.hotKeys[sc], s.current) {
+		s.hotKeys[sc] = append(s.hotKeys[sc], s.current)
+	}
+	if s.hotKeyTarget == s.current && s.hotKey == sc {
+		s.hotKeyTarget = nil
+		return true
+	}
+	return false
+}
+
+// Mnemonic registers a key that activates the current component when it is pressed together with Alt.
+// It returns true if the key was pressed.
+func (s *State) Mnemonic(k Key) bool {
+	return s.HotKey(Shortcut{k, Alt})
+}
+
+func containsComponent(list []Component, c Component) bool {
+	for _, l := range list {
+		if l == c {
+			return true
+		}
+	}
+	return false
+}
+
+// HandleHotKeys delivers key events matching a hot key that was registered during the last update.
+// This method should be called by the root component before updating its children.
+func (s *State) HandleHotKeys() {
+	s.hotKeys, s.lastHotKeys = s.lastHotKeys, s.hotKeys
+	for sc := range s.hotKeys {
+		delete(s.hotKeys, sc)
+	}
+	s.hotKeyTarget = nil
+	for _, k := range s.keyPresses {
+		sc := Shortcut{k, s.modifiers &^ (CapsLock | NumLock)}
+		if c := s.lastHotKeys[sc]; len(c) > 0 {
+			if sc == s.hotKey {
+				s.hotKeyCount++
+			} else {
+				s.hotKeyCount = 0
+			}
+			s.ConsumeKeyPress(k)
+			s.textInput = ""
+			s.hotKey, s.hotKeyTarget = sc, c[s.hotKeyCount%len(c)]
+			s.update = true
+			return
+		}
+	}
+}
+
 // TextInput returns the string that would be generated by key inputs.
 // Key presses that contributed to the text input will still appear in KeyPresses().
 func (s *State) TextInput() string {
@@ -231,21 +350,34 @@ func (s *State) SetRoot(r Root) {
 	s.root = r
 }
 
+// Root returns the root component set with SetRoot.
+func (s *State) Root() Root {
+	return s.root
+}
+
 // OpenDialog displays the given component as a dialog.
 // While a dialog is open, other components do not receive any events.
-// Only one dialog may be open at a time.
+// Dialogs can be nested, a new dialog is shown on top of the previously opened ones.
 func (s *State) OpenDialog(d Component) {
 	if s.root != nil {
 		s.root.OpenDialog(d)
+		s.dialogFocus = append(s.dialogFocus, s.focused)
 		s.focused = d
 		s.update = true
 	}
 }
 
-// CloseDialog closes the currently open dialog, if any.
+// CloseDialog closes the topmost dialog, if any.
+// The keyboard focus is given back to the component that had it when the dialog was opened.
+// This only works if dialogs are opened and closed through the State, not by calling the Root's methods directly.
 func (s *State) CloseDialog() {
 	if s.root != nil {
 		s.root.CloseDialog()
+		if l := len(s.dialogFocus) - 1; l >= 0 {
+			s.focused = s.dialogFocus[l]
+			s.dialogFocus[l] = nil
+			s.dialogFocus = s.dialogFocus[:l]
+		}
 		s.update = true
 	}
 }
@@ -260,6 +392,17 @@ func (s *State) OpenPopup(bounds image.Rectangle, d Component) Popup {
 	return nil
 }
 
+// OpenNonModalPopup displays the given component as a popup without moving the keyboard focus.
+// Unlike with OpenPopup, other components keep receiving events while the popup is open.
+// If the root does not implement NonModalRoot, no popup is opened and nil is returned.
+func (s *State) OpenNonModalPopup(bounds image.Rectangle, d Component) Popup {
+	if r, ok := s.root.(NonModalRoot); ok {
+		s.update = true
+		return r.OpenNonModalPopup(bounds.Add(s.bounds.Min), d)
+	}
+	return nil
+}
+
 // ClosePopups closes all popups.
 func (s *State) ClosePopups() {
 	if s.root != nil {
@@ -269,6 +412,7 @@ func (s *State) ClosePopups() {
 }
 
 // HasPopups returns wether any popups are currently open.
+// Non-modal popups are not taken into account.
 func (s *State) HasPopups() bool {
 	return s.root != nil && s.root.HasPopups()
 }
@@ -315,6 +459,13 @@ func (s *State) RequestAnimation() {
 	s.animation = true
 }
 
+// Waker returns a function that requests an update, it may be called from any goroutine.
+// The function is nil if the backend does not support this,
+// components that wait for other goroutines then have to check for changes with RequestAnimation.
+func (s *State) Waker() func() {
+	return s.wake
+}
+
 // RequestRefocus requests that the component receiving mouse events should be determined again.
 // This method should rarely be called by normal components.
 func (s *State) RequestRefocus() {
@@ -354,6 +505,15 @@ func (s *State) DrawChild(g *draw.Buffer, bounds image.Rectangle, c Component) {
 	s.current = this
 }
 
+// UpdateChildWithoutMouse calls another component's Update method without passing on mouse events,
+// unless the mouse is grabbed by one of its descendants.
+func (s *State) UpdateChildWithoutMouse(g *draw.Buffer, bounds image.Rectangle, c Component) {
+	h := s.hovered
+	s.hovered = false
+	s.UpdateChild(g, bounds, c)
+	s.hovered = h
+}
+
 // UpdateChild calls another component's Update method with a State that will deliver the correct events.
 func (s *State) UpdateChild(g *draw.Buffer, bounds image.Rectangle, c Component) {
 	g.Push(bounds)
@@ -377,8 +537,11 @@ func (s *State) UpdateChild(g *draw.Buffer, bounds image.Rectangle, c Component)
 	s.focusable = false
 	if s.focused == s.current {
 		s.focusNext = true
+		s.focusPath = append(s.focusPath[:0], s.path...)
 	}
+	s.path = append(s.path, c)
 	c.Update(g, s)
+	s.path = s.path[:len(s.path)-1]
 	if s.focusable {
 		s.lastFocusable = c
 	}
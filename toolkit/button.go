@@ -9,10 +9,12 @@ import (
 type Button struct {
 	Action func(*ui.State)
 	Theme  *Theme
-	Text   string
-	Icon   string
-	text   text.Text
-	anim   float32
+	// Text may contain a mnemonic like "&Save", a literal "&" must be written as "&&".
+	Text string
+	Icon string
+	text text.Text
+	mn   mnemonic
+	anim float32
 }
 
 func NewButton(text string, action func(*ui.State)) *Button {
@@ -26,23 +28,33 @@ func NewButtonIcon(icon, text string, action func(*ui.State)) *Button {
 func (b *Button) SetTheme(theme *Theme) { b.Theme = theme }
 
 func (b *Button) PreferredSize(fonts draw.FontLookup) (int, int) {
-	w, h := b.text.SizeIcon(b.Text, b.Theme.Font("buttonText"), b.Icon, 5, fonts)
+	w, h := b.text.SizeIcon(b.mn.parse(b.Text), b.Theme.Font("buttonText"), b.Icon, 5, fonts)
 	return w + 20, h + 20
 }
 
 func (b *Button) Update(g *draw.Buffer, state *ui.State) {
 	w, h := g.Size()
 	animate(state, &b.anim, 8, state.IsHovered())
-	g.Fill(draw.WH(w, h), draw.Blend(b.Theme.Color("buttonBackground"), b.Theme.Color("buttonHovered"), b.anim))
+	g.RoundedFill(draw.WH(w, h), b.Theme.Size("cornerRadius"), draw.Blend(b.Theme.Color("buttonBackground"), b.Theme.Color("buttonHovered"), b.anim))
 	color := b.Theme.Color("buttonText")
 	if state.HasKeyboardFocus() {
 		color = b.Theme.Color("buttonFocused")
 	}
-	b.text.DrawCenteredIcon(g, draw.WH(w, h), b.Text, b.Theme.Font("buttonText"), color, b.Icon, 5)
-	action := state.MouseClick(ui.MouseLeft)
+	text := b.mn.parse(b.Text)
+	font := b.Theme.Font("buttonText")
+	b.text.DrawCenteredIcon(g, draw.WH(w, h), text, font, color, b.Icon, 5)
+	tw, th := b.text.Size(text, font, g.FontLookup)
+	if b.Icon != "" {
+		b.mn.draw(g, state, (w-tw+5+th)/2, draw.WH(w, h), font, color)
+	} else {
+		b.mn.draw(g, state, (w-tw)/2, draw.WH(w, h), font, color)
+	}
+	action := state.MouseClick(ui.MouseLeft) || b.mn.pressed(state)
 	for _, k := range state.KeyPresses() {
 		if k == ui.KeySpace || k == ui.KeyEnter {
 			action = true
+		} else {
+			state.IgnoreKeyPress(k)
 		}
 	}
 	if b.Action != nil && action {
@@ -0,0 +1,27 @@
+package toolkit
+
+import (
+	"github.com/jfreymuth/ui"
+	"github.com/jfreymuth/ui/draw"
+)
+
+// A Canvas is a component whose content is drawn by a function, for example with the vector drawing methods of draw.Buffer.
+type Canvas struct {
+	Width, Height int
+	// Draw is called on every update, the size of the canvas is available from g.Size.
+	Draw func(g *draw.Buffer, state *ui.State)
+}
+
+func NewCanvas(w, h int, f func(*draw.Buffer, *ui.State)) *Canvas {
+	return &Canvas{Width: w, Height: h, Draw: f}
+}
+
+func (c *Canvas) PreferredSize(fonts draw.FontLookup) (int, int) {
+	return c.Width, c.Height
+}
+
+func (c *Canvas) Update(g *draw.Buffer, state *ui.State) {
+	if c.Draw != nil {
+		c.Draw(g, state)
+	}
+}
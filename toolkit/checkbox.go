@@ -10,9 +10,11 @@ type CheckBox struct {
 	Checked bool
 	Changed func(*ui.State, bool)
 	Theme   *Theme
-	Text    string
-	text    text.Text
-	anim    float32
+	// Text may contain a mnemonic like "&Save", a literal "&" must be written as "&&".
+	Text string
+	text text.Text
+	mn   mnemonic
+	anim float32
 }
 
 func NewCheckBox(text string) *CheckBox {
@@ -20,18 +22,21 @@ func NewCheckBox(text string) *CheckBox {
 }
 
 func (c *CheckBox) PreferredSize(fonts draw.FontLookup) (int, int) {
-	w, h := c.text.Size(c.Text, DefaultTheme.Font("buttonText"), fonts)
+	w, h := c.text.Size(c.mn.parse(c.Text), DefaultTheme.Font("buttonText"), fonts)
 	return w + h + 30, h + 20
 }
 
 func (c *CheckBox) Update(g *draw.Buffer, state *ui.State) {
 	w, h := g.Size()
-	_, s := c.text.Size(c.Text, DefaultTheme.Font("buttonText"), g.FontLookup)
+	text := c.mn.parse(c.Text)
+	_, s := c.text.Size(text, DefaultTheme.Font("buttonText"), g.FontLookup)
 
-	action := state.MouseClick(ui.MouseLeft)
+	action := state.MouseClick(ui.MouseLeft) || c.mn.pressed(state)
 	for _, k := range state.KeyPresses() {
 		if k == ui.KeySpace || k == ui.KeyEnter {
 			action = true
+		} else {
+			state.IgnoreKeyPress(k)
 		}
 	}
 	if action {
@@ -54,5 +59,6 @@ func (c *CheckBox) Update(g *draw.Buffer, state *ui.State) {
 	if state.HasKeyboardFocus() {
 		color = DefaultTheme.Color("buttonFocused")
 	}
-	c.text.DrawLeft(g, draw.XYXY(s+20, 0, w, h), c.Text, DefaultTheme.Font("buttonText"), color)
+	c.text.DrawLeft(g, draw.XYXY(s+20, 0, w, h), text, DefaultTheme.Font("buttonText"), color)
+	c.mn.draw(g, state, s+20, draw.WH(w, h), DefaultTheme.Font("buttonText"), color)
 }
@@ -1,7 +1,8 @@
 package toolkit
 
 import (
-	"image"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/jfreymuth/ui"
 	"github.com/jfreymuth/ui/draw"
@@ -9,17 +10,40 @@ import (
 
 type ComboBox struct {
 	List
-	sv   *ScrollView
-	anim float32
+	// If Editable is true, the combo box contains a text field, and typing filters the list.
+	// Text that does not match any item can be committed with the enter key,
+	// in that case Selected is set to -1, and Changed is called with an item that is not part of the list.
+	Editable bool
+	sv       *ScrollView
+	anim     float32
+	field    TextField
+	sugg     suggestions
+	matches  []int
+	shown    int
 }
 
 func NewComboBox() *ComboBox {
-	c := &ComboBox{List: List{Theme: DefaultTheme}}
+	c := &ComboBox{List: List{Theme: DefaultTheme, noMenu: true}, shown: -1}
 	c.sv = NewScrollView(&c.List)
+	c.field = TextField{Theme: DefaultTheme, Editable: true, Action: func(state *ui.State, _ string) { c.commit(state) }}
 	return c
 }
 
-func (c *ComboBox) SetTheme(theme *Theme) { c.sv.SetTheme(theme) }
+func (c *ComboBox) SetTheme(theme *Theme) {
+	c.sv.SetTheme(theme)
+	c.field.SetTheme(theme)
+}
+
+// Text returns the text of the selected item, or the text entered by the user if the combo box is editable.
+func (c *ComboBox) Text() string {
+	if c.Editable {
+		return c.field.Text
+	}
+	if c.Selected >= 0 && c.Selected < len(c.Items) {
+		return c.Items[c.Selected].Text
+	}
+	return ""
+}
 
 func (c *ComboBox) PreferredSize(fonts draw.FontLookup) (int, int) {
 	if len(c.Items) == 0 {
@@ -31,6 +55,10 @@ func (c *ComboBox) PreferredSize(fonts draw.FontLookup) (int, int) {
 }
 
 func (c *ComboBox) Update(g *draw.Buffer, state *ui.State) {
+	if c.Editable {
+		c.updateEditable(g, state)
+		return
+	}
 	w, h := g.Size()
 	var item ListItem
 	if len(c.Items) > 0 {
@@ -42,29 +70,159 @@ func (c *ComboBox) Update(g *draw.Buffer, state *ui.State) {
 		item = c.Items[c.Selected]
 	}
 	animate(state, &c.anim, 8, state.IsHovered())
-	g.Fill(draw.WH(w, h), draw.Blend(c.Theme.Color("buttonBackground"), c.Theme.Color("buttonHovered"), c.anim))
+	g.RoundedFill(draw.WH(w, h), c.Theme.Size("cornerRadius"), draw.Blend(c.Theme.Color("buttonBackground"), c.Theme.Color("buttonHovered"), c.anim))
 	_, th := item.text.Size(item.Text, c.Theme.Font("text"), g.FontLookup)
 	item.text.DrawLeftIcon(g, draw.XYXY(15, 0, w-th-10, h), item.Text, c.Theme.Font("text"), c.Theme.Color("text"), item.Icon, 3)
 	g.Icon(draw.XYXY(w-th-10, 0, w-10, h), "down", c.Theme.Color("text"))
 	if state.MouseClick(ui.MouseLeft) {
-		pw, ph := c.List.PreferredSize(g.FontLookup)
-		pw, ph = pw+6, ph+6
-		win := state.WindowBounds()
-		spaceAbove := -win.Min.Y
-		spaceBelow := win.Max.Y - h
-		var r image.Rectangle
-		if ph <= spaceBelow {
-			r = draw.XYWH(0, h, pw, ph)
-		} else if ph <= spaceAbove {
-			r = draw.XYWH(0, -ph, pw, ph)
-		} else if spaceAbove > spaceBelow {
-			r = draw.XYWH(0, -spaceAbove, pw+15, spaceAbove)
+		bg := &menuBackground{c.sv, c.Theme}
+		pw, ph := bg.size(c.List.PreferredSize(g.FontLookup))
+		state.OpenPopup(popupBounds(state, w, h, pw, ph), bg)
+	}
+}
+
+func (c *ComboBox) updateEditable(g *draw.Buffer, state *ui.State) {
+	w, h := g.Size()
+	if c.Selected >= len(c.Items) {
+		c.Selected = len(c.Items) - 1
+	}
+	if c.Selected != c.shown {
+		if c.Selected >= 0 {
+			c.setFieldText(c.Items[c.Selected].Text)
+		}
+		c.shown = c.Selected
+	}
+	if state.KeyboardFocus() == &c.field {
+		keys := append([]ui.Key(nil), state.PeekKeyPresses()...)
+		for _, k := range keys {
+			switch k {
+			case ui.KeyUp:
+				if c.sugg.isOpen() {
+					c.sugg.move(-1)
+				}
+			case ui.KeyDown:
+				if c.sugg.isOpen() {
+					c.sugg.move(1)
+				} else {
+					c.sugg.show(g, state, &c.field, c.filter(""), c.Theme, c.accept)
+				}
+			case ui.KeyEscape:
+				if c.sugg.isOpen() {
+					// the key must not close a dialog containing the combo box
+					c.sugg.close()
+					state.ConsumeKeyPress(k)
+				}
+			}
+		}
+	}
+	th := g.FontLookup.Metrics(c.Theme.Font("text")).LineHeight()
+	text := c.field.Text
+	state.UpdateChild(g, draw.XYXY(10, 0, w-th-10, h), &c.field)
+	animate(state, &c.anim, 8, state.IsHovered())
+	g.RoundedFill(draw.XYXY(w-th-15, 0, w, h), c.Theme.Size("cornerRadius"), draw.Blend(c.Theme.Color("buttonBackground"), c.Theme.Color("buttonHovered"), c.anim))
+	g.Icon(draw.XYXY(w-th-10, 0, w-10, h), "down", c.Theme.Color("text"))
+	if state.MouseClick(ui.MouseLeft) {
+		if c.sugg.isOpen() {
+			c.sugg.close()
 		} else {
-			r = draw.XYWH(0, h, pw+15, spaceBelow)
+			c.sugg.show(g, state, &c.field, c.filter(""), c.Theme, c.accept)
 		}
-		if r.Dx() < w {
-			r.Max.X = r.Min.X + w
+		state.SetKeyboardFocus(&c.field)
+	} else if c.field.Text != text {
+		c.sugg.show(g, state, &c.field, c.filter(c.field.Text), c.Theme, c.accept)
+	} else if state.KeyboardFocus() != &c.field {
+		c.sugg.close()
+	}
+}
+
+// filter returns the items containing the given text, items that start with the text come first.
+func (c *ComboBox) filter(s string) []ListItem {
+	var prefix, substr []ListItem
+	var pi, si []int
+	for i, item := range c.Items {
+		j, k := indexFold(item.Text, s)
+		if j < 0 {
+			continue
+		}
+		item := ListItem{Icon: item.Icon, Text: item.Text, match: [2]int{j, k}}
+		if j == 0 {
+			prefix, pi = append(prefix, item), append(pi, i)
+		} else {
+			substr, si = append(substr, item), append(si, i)
 		}
-		state.OpenPopup(r, &menuBackground{c.sv, c.Theme})
 	}
+	c.matches = append(pi, si...)
+	return append(prefix, substr...)
+}
+
+// indexFold returns the start and end of the first occurrence of substr in s under Unicode case folding, or -1, -1.
+// The offsets refer to s, the length of the match may differ from the length of substr.
+func indexFold(s, substr string) (int, int) {
+	n := utf8.RuneCountInString(substr)
+	for i := 0; i < len(s); {
+		j := i
+		for k := 0; k < n && j < len(s); k++ {
+			_, size := utf8.DecodeRuneInString(s[j:])
+			j += size
+		}
+		if strings.EqualFold(s[i:j], substr) {
+			return i, j
+		}
+		_, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+	}
+	if substr == "" {
+		return 0, 0
+	}
+	return -1, -1
+}
+
+func (c *ComboBox) accept(state *ui.State, item ListItem) {
+	c.sugg.close()
+	// the matches may have changed since the item was shown, so they are only used if they still agree with it
+	if s := c.sugg.Selected; s >= 0 && s < len(c.matches) && c.matches[s] < len(c.Items) && c.Items[c.matches[s]].Text == item.Text {
+		c.selectItem(state, c.matches[s])
+		return
+	}
+	for i := range c.Items {
+		if c.Items[i].Text == item.Text {
+			c.selectItem(state, i)
+			return
+		}
+	}
+}
+
+// commit selects the highlighted suggestion, or the item matching the entered text.
+// If there is no such item, the entered text is committed as is.
+func (c *ComboBox) commit(state *ui.State) {
+	if c.sugg.isOpen() {
+		c.accept(state, c.sugg.selected())
+		return
+	}
+	for i, item := range c.Items {
+		if item.Text == c.field.Text {
+			c.selectItem(state, i)
+			return
+		}
+	}
+	c.Selected, c.shown = -1, -1
+	if c.Changed != nil {
+		c.Changed(state, ListItem{Text: c.field.Text})
+		state.RequestUpdate()
+	}
+}
+
+func (c *ComboBox) selectItem(state *ui.State, i int) {
+	c.Selected, c.shown = i, i
+	c.setFieldText(c.Items[i].Text)
+	if c.Changed != nil {
+		c.Changed(state, c.Items[i])
+		state.RequestUpdate()
+	}
+}
+
+func (c *ComboBox) setFieldText(text string) {
+	c.field.Text = text
+	c.field.cursor = len(text)
+	c.field.selectionStart = 0
 }
@@ -0,0 +1,55 @@
+package toolkit
+
+import (
+	"image"
+
+	"github.com/jfreymuth/ui"
+	"github.com/jfreymuth/ui/draw"
+)
+
+// A ContextMenu opens a popup menu when its content is right-clicked,
+// or when the menu key is pressed while the content has keyboard focus.
+// Components that provide their own context menu (e.g. TextField) take precedence.
+type ContextMenu struct {
+	Content ui.Component
+	Menu    *Menu
+}
+
+func NewContextMenu(content ui.Component, menu *Menu) *ContextMenu {
+	return &ContextMenu{Content: content, Menu: menu}
+}
+
+func (c *ContextMenu) SetTheme(theme *Theme) {
+	SetTheme(c.Content, theme)
+	if c.Menu != nil {
+		c.Menu.SetTheme(theme)
+	}
+}
+
+func (c *ContextMenu) PreferredSize(fonts draw.FontLookup) (int, int) {
+	return c.Content.PreferredSize(fonts)
+}
+
+func (c *ContextMenu) Update(g *draw.Buffer, state *ui.State) {
+	w, h := g.Size()
+	key := false
+	if c.Menu != nil && state.HasKeyboardFocusWithin(c.Content) {
+		for _, k := range state.PeekKeyPresses() {
+			if k == ui.KeyMenu {
+				state.ConsumeKeyPress(k)
+				key = true
+				break
+			}
+		}
+	}
+	state.UpdateChild(g, draw.WH(w, h), c.Content)
+	if c.Menu == nil {
+		return
+	}
+	if key {
+		c.Menu.OpenPopupMenu(image.Point{}, state, g.FontLookup)
+	} else if state.MouseButtonDownWithin(ui.MouseRight) && !state.HasPopups() {
+		c.Menu.OpenPopupMenu(state.MousePos(), state, g.FontLookup)
+		state.InitiateDrag(ui.MenuDrag)
+	}
+}
@@ -75,13 +75,30 @@ func ShowInputDialog(state *ui.State, title, message, button, cancel string, act
 }
 
 func ShowOpenDialog(state *ui.State, fc *FileChooser, title, open, cancel string, action func(*ui.State, string)) {
-	fc.SetLabels(open, cancel, true)
-	fc.Action = action
-	state.OpenDialog(NewFrame("open", title, fc, DefaultTheme.Color("titleBackground")))
+	showFileDialog(state, fc, "open", title, open, cancel, true, false, false)
+	fc.Action, fc.MultiAction = action, nil
+}
+
+// ShowOpenFilesDialog shows a file chooser that allows selecting multiple files.
+func ShowOpenFilesDialog(state *ui.State, fc *FileChooser, title, open, cancel string, action func(*ui.State, []string)) {
+	showFileDialog(state, fc, "open", title, open, cancel, true, true, false)
+	fc.Action, fc.MultiAction = nil, action
 }
 
 func ShowSaveDialog(state *ui.State, fc *FileChooser, title, save, cancel string, action func(*ui.State, string)) {
-	fc.SetLabels(save, cancel, false)
-	fc.Action = action
-	state.OpenDialog(NewFrame("save", title, fc, DefaultTheme.Color("titleBackground")))
+	showFileDialog(state, fc, "save", title, save, cancel, false, false, false)
+	fc.Action, fc.MultiAction = action, nil
+}
+
+// ShowDirectoryDialog shows a file chooser for choosing a directory.
+func ShowDirectoryDialog(state *ui.State, fc *FileChooser, title, choose, cancel string, action func(*ui.State, string)) {
+	showFileDialog(state, fc, "folder", title, choose, cancel, true, false, true)
+	fc.Action, fc.MultiAction = action, nil
+}
+
+func showFileDialog(state *ui.State, fc *FileChooser, icon, title, action, cancel string, existing, multi, dirs bool) {
+	fc.SetLabels(action, cancel, existing)
+	fc.files.MultiSelect = multi
+	fc.SetDirectoryMode(dirs)
+	state.OpenDialog(NewFrame(icon, title, fc, DefaultTheme.Color("titleBackground")))
 }
@@ -1,36 +1,191 @@
 package toolkit
 
 import (
-	"os"
+	"image"
+	"io/fs"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/jfreymuth/ui"
 	"github.com/jfreymuth/ui/draw"
+	"github.com/jfreymuth/ui/text"
 )
 
 type FileChooser struct {
-	root      ui.Component
-	files     *List
-	ab, cb    *Button
-	nameField TextField
-	path      string
-	Action    func(*ui.State, string)
+	root       ui.Component
+	files      *List
+	ab, cb     *Button
+	nameField  TextField
+	location   TextField
+	crumbs     breadcrumbs
+	hidden     *CheckBox
+	filter     *ComboBox
+	filters    []FileFilter
+	places     *List
+	placePaths []string
+	unbookmark *MenuItem
+	header     fileHeader
+	preview    filePreview
+	noPreview  bool
+	view       *Button
+	dirs       bool
+	details    bool
+	recent     bool
+	sortBy     FileColumn
+	sortDesc   bool
+	fs         fileSystem
+	entries    []fs.FileInfo // the unfiltered contents of the directory
+	names      []string      // the paths of the recently used files
+	infos      map[string]fs.FileInfo
+	path       string
+	selectName string // selected when the directory has been loaded
+	err        error
+	errText    text.Text
+	loadAnim   float32
+	Action     func(*ui.State, string)
+	// MultiAction is called instead of Action if it is set, with all chosen paths.
+	MultiAction func(*ui.State, []string)
+	// Confirm is called before the dialog is closed, e.g. to ask whether an existing file should be overwritten.
+	// The dialog is only closed and Action is only called once accept is called.
+	// If several paths are chosen, Confirm is called for each of them.
+	// If Confirm is nil, the user is asked before overwriting an existing file in save mode.
+	Confirm func(state *ui.State, path string, accept func(*ui.State))
+	// DefaultExtension is appended to typed file names without an extension in save mode, e.g. ".txt".
+	DefaultExtension string
+
+	load         fileTask // reads the shown directory or the recently used files
+	check        fileTask // checks whether a file exists before it is overwritten
+	lookup       fileTask // reads the directory the location field's suggestions are taken from
+	suggestDir   string
+	suggestFiles []fs.FileInfo // the contents of suggestDir, nil while it is read
+
+	bookmarksLoad    fileTask
+	bookmarksSave    fileTask
+	readingBookmarks bool
+}
+
+// A FileFilter restricts the files shown by a FileChooser.
+type FileFilter struct {
+	// Name is shown in the file chooser, e.g. "Go files (*.go)".
+	Name string
+	// Patterns are matched against file names using filepath.Match, e.g. "*.go".
+	// A filter without patterns matches all files.
+	Patterns []string
+}
+
+// Match returns true if a file name matches any of the filter's patterns.
+func (f FileFilter) Match(name string) bool {
+	if len(f.Patterns) == 0 {
+		return true
+	}
+	name = strings.ToLower(name)
+	for _, p := range f.Patterns {
+		if ok, _ := filepath.Match(strings.ToLower(p), name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// A FileColumn is a column of the details view of a FileChooser.
+type FileColumn int
+
+const (
+	ColumnName FileColumn = iota
+	ColumnSize
+	ColumnModified
+	ColumnType
+)
+
+// A fileTask accesses the file system in the background, so that slow file systems don't block the UI.
+// Only the result of the most recently started work is used.
+type fileTask struct {
+	mu      sync.Mutex
+	gen     int
+	running bool
+	done    func(*ui.State)
+	wake    func()
+}
+
+// start runs work on a new goroutine, discarding the result of any previous work.
+// The function returned by work is called on the UI goroutine by poll.
+func (t *fileTask) start(work func() func(*ui.State)) {
+	t.mu.Lock()
+	t.gen++
+	gen := t.gen
+	t.running, t.done = true, nil
+	t.mu.Unlock()
+	go func() {
+		done := work()
+		t.mu.Lock()
+		if t.gen != gen {
+			t.mu.Unlock()
+			return
+		}
+		t.running, t.done = false, done
+		wake := t.wake
+		t.mu.Unlock()
+		if wake != nil {
+			wake()
+		}
+	}()
+}
+
+// poll calls the result of finished work, and returns true if work is still running.
+func (t *fileTask) poll(state *ui.State) bool {
+	t.mu.Lock()
+	t.wake = state.Waker()
+	done, running := t.done, t.running
+	t.done = nil
+	t.mu.Unlock()
+	if done != nil {
+		done(state)
+		state.RequestUpdate()
+	}
+	return running
 }
 
 func NewFileChooser() *FileChooser {
-	f := &FileChooser{}
+	f := &FileChooser{fs: osFileSystem{}}
 	f.ab = NewButton("", f.action)
 	f.cb = NewButton("", (*ui.State).CloseDialog)
 	f.files = NewList()
-	f.files.Changed = func(state *ui.State, i ListItem) { f.nameField.Text = (i.Text) }
-	f.files.Action = func(state *ui.State, i ListItem) { f.action(state) }
-	f.root = &Container{
-		Center: NewScrollView(f.files),
-		Bottom: NewBar(1, NewButtonIcon("left.arrow", "", f.back), &f.nameField, f.ab, f.cb),
+	f.files.Changed = func(state *ui.State, i ListItem) { f.selectionChanged() }
+	f.files.Action = func(state *ui.State, i ListItem) {
+		if i.Icon == "folder" {
+			f.enter()
+		} else {
+			f.action(state)
+		}
 	}
 	f.nameField = *NewTextField()
+	f.location = *NewTextField()
+	f.location.Suggest = f.complete
+	f.location.Action = func(state *ui.State, path string) { f.navigate(path) }
+	f.crumbs.f = f
+	f.hidden = NewCheckBox("&Hidden")
+	f.hidden.Changed = func(*ui.State, bool) { f.refresh() }
+	f.filter = NewComboBox()
+	f.filter.Changed = func(*ui.State, ListItem) { f.refresh() }
+	f.view = NewButtonIcon("view.details", "", func(*ui.State) { f.SetDetailsView(!f.details) })
+	f.header.f = f
+	f.preview.f = f
+	f.preview.cache.max = previewCacheSize
+	f.initPlaces()
+	f.root = &Container{
+		Top: NewStack(
+			NewBar(1, NewButtonIcon("left.arrow", "", f.back), &f.crumbs, f.hidden,
+				&fileOptional{f.isOS, NewButtonIcon("bookmark.add", "", func(*ui.State) { f.AddBookmark(f.path) })}, f.view),
+			NewPadding(&f.location, 3),
+		),
+		Left:   NewScrollView(f.places),
+		Center: &fileList{f, NewScrollView(f.files)},
+		Right:  &fileOptional{func() bool { return !f.noPreview }, &f.preview},
+		Bottom: NewBar(0, &f.nameField, &fileOptional{func() bool { return len(f.filters) > 0 }, f.filter}, f.ab, f.cb),
+	}
 	f.set(".")
 	return f
 }
@@ -45,77 +200,723 @@ func (f *FileChooser) SetLabels(action, cancel string, existing bool) {
 	f.nameField.Editable = !existing
 }
 
+// SetMultiSelect sets whether multiple files can be selected.
+func (f *FileChooser) SetMultiSelect(multi bool) {
+	f.files.MultiSelect = multi
+	f.refresh()
+}
+
+// SetDirectoryMode sets whether the file chooser is used to choose a directory instead of a file.
+// In directory mode, only directories are shown, and the current directory is chosen if none is selected.
+func (f *FileChooser) SetDirectoryMode(dirs bool) {
+	f.dirs = dirs
+	f.refresh()
+}
+
+// SetDetailsView sets whether the size, modification time and type of the files are shown.
+func (f *FileChooser) SetDetailsView(details bool) {
+	f.details = details
+	if details {
+		f.view.Icon = "view.list"
+	} else {
+		f.view.Icon = "view.details"
+		f.files.Columns = nil
+	}
+}
+
+// SetPreview sets whether a preview of the selected file is shown.
+// Images are shown as a thumbnail, text files show their first lines.
+func (f *FileChooser) SetPreview(preview bool) {
+	f.noPreview = !preview
+}
+
+// SetSort sets the column the files are sorted by. Directories are always shown first.
+func (f *FileChooser) SetSort(column FileColumn, descending bool) {
+	f.sortBy, f.sortDesc = column, descending
+	f.sort()
+}
+
+// SetPath sets the directory that is shown.
+// It is read in the background, the file chooser shows a loading indicator until it is done.
 func (f *FileChooser) SetPath(path string) {
 	f.set(path)
 }
 
+// SetFS makes the file chooser show the files of fsys instead of the OS file system, starting at its root.
+// Paths passed to SetPath may start with a slash, the paths passed to Action are valid fs.FS paths.
+// If fsys implements WritableFS, a file can only be saved if CanWrite succeeds.
+// If fsys is nil, the OS file system is shown.
+func (f *FileChooser) SetFS(fsys fs.FS) {
+	if fsys == nil {
+		f.fs = osFileSystem{}
+		f.set(".")
+	} else {
+		f.fs = ioFileSystem{fsys}
+		f.set("/")
+	}
+}
+
+func (f *FileChooser) isOS() bool {
+	_, ok := f.fs.(osFileSystem)
+	return ok
+}
+
+// SetFilters sets the file types the user can choose from. The first filter is selected.
+func (f *FileChooser) SetFilters(filters ...FileFilter) {
+	f.filters = filters
+	f.filter.Items = nil
+	for _, filter := range filters {
+		f.filter.AddItem(filter.Name)
+	}
+	f.filter.Selected = 0
+	f.refresh()
+}
+
+// SelectedFilter returns the selected file filter.
+func (f *FileChooser) SelectedFilter() FileFilter {
+	if f.filter.Selected >= 0 && f.filter.Selected < len(f.filters) {
+		return f.filters[f.filter.Selected]
+	}
+	return FileFilter{}
+}
+
+// SetShowHidden sets whether hidden files are shown.
+func (f *FileChooser) SetShowHidden(show bool) {
+	f.hidden.Checked = show
+	f.refresh()
+}
+
 func (f *FileChooser) PreferredSize(fonts draw.FontLookup) (int, int) {
-	return 400, 320
+	return 720, 400
 }
 
 func (f *FileChooser) Update(g *draw.Buffer, state *ui.State) {
+	f.poll(state)
 	w, h := g.Size()
 	state.UpdateChild(g, draw.WH(w, h), f.root)
 }
 
 func (f *FileChooser) action(state *ui.State) {
-	s := f.files.Items[f.files.Selected]
-	if s.Text == f.nameField.Text {
-		if s.Icon == "folder" {
-			f.enter()
-		} else {
-			state.CloseDialog()
-			if f.Action != nil {
-				f.Action(state, filepath.Join(f.path, s.Text))
+	if f.files.MultiSelect {
+		if sel := f.files.SelectedItems(); len(sel) > 1 {
+			var paths []string
+			for _, item := range sel {
+				if (item.Icon == "folder") == f.dirs {
+					paths = append(paths, f.itemPath(item.Text))
+				}
+			}
+			if len(paths) > 0 {
+				f.accept(state, paths...)
 			}
+			return
 		}
+	}
+	if f.files.Selected < len(f.files.Items) {
+		s := f.files.Items[f.files.Selected]
+		if s.Text == f.nameField.Text {
+			if s.Icon == "folder" && !f.dirs {
+				f.enter()
+			} else {
+				f.accept(state, f.itemPath(s.Text))
+			}
+			return
+		}
+	}
+	if f.dirs && f.nameField.Text == "" && !f.recent {
+		f.accept(state, f.path)
 	} else if f.nameField.Editable && f.nameField.Text != "" {
+		name := f.nameField.Text
+		if !f.dirs && f.DefaultExtension != "" && filepath.Ext(name) == "" {
+			name += f.DefaultExtension
+		}
+		f.accept(state, f.itemPath(name))
+	}
+}
+
+func (f *FileChooser) accept(state *ui.State, paths ...string) {
+	save := f.nameField.Editable && !f.dirs
+	if iofs, ok := f.fs.(ioFileSystem); ok && save {
+		if w, ok := iofs.fsys.(WritableFS); ok {
+			for _, p := range paths {
+				if err := w.CanWrite(f.fs.name(p)); err != nil {
+					f.err = err
+					return
+				}
+			}
+		}
+	}
+	for i, p := range paths {
+		paths[i] = f.fs.name(p)
+	}
+	accept := func(state *ui.State) {
 		state.CloseDialog()
-		if f.Action != nil {
-			f.Action(state, filepath.Join(f.path, f.nameField.Text))
+		if f.isOS() {
+			addRecent(paths...)
+		}
+		if f.MultiAction != nil {
+			f.MultiAction(state, paths)
+		} else if f.Action != nil {
+			f.Action(state, paths[0])
+		}
+	}
+	confirm := f.Confirm
+	if confirm == nil && save {
+		confirm = f.confirmOverwrite
+	}
+	if confirm != nil {
+		for i := len(paths) - 1; i >= 0; i-- {
+			path, next := paths[i], accept
+			accept = func(state *ui.State) { confirm(state, path, next) }
+		}
+	}
+	accept(state)
+}
+
+func (f *FileChooser) confirmOverwrite(state *ui.State, path string, accept func(*ui.State)) {
+	fsys := f.fs
+	f.check.start(func() func(*ui.State) {
+		_, err := fsys.stat(path)
+		return func(state *ui.State) {
+			if err != nil {
+				accept(state)
+				return
+			}
+			// the confirmation is shown on top of the file chooser, which stays open if it is cancelled
+			ShowConfirmDialog(state, "Confirm", fsys.base(path)+" already exists.\nDo you want to replace it?", "Replace", "Cancel", accept)
 		}
+	})
+}
+
+// selectionChanged shows the selected file names in the name field.
+func (f *FileChooser) selectionChanged() {
+	sel := f.files.SelectedItems()
+	if len(sel) == 1 || !f.files.MultiSelect && len(sel) > 0 {
+		f.nameField.Text = sel[0].Text
+	} else if len(sel) > 1 {
+		names := make([]string, len(sel))
+		for i, item := range sel {
+			names[i] = strconv.Quote(item.Text)
+		}
+		f.nameField.Text = strings.Join(names, " ")
 	}
 }
 
+// set starts reading a directory in the background.
 func (f *FileChooser) set(p string) {
-	p, _ = filepath.Abs(p)
-	dir, err := os.Open(p)
-	if err != nil {
-		return
+	p = f.fs.abs(p)
+	f.reset(p, false)
+	fsys := f.fs
+	f.load.start(func() func(*ui.State) {
+		files, err := fsys.readDir(p)
+		return func(*ui.State) { f.loaded(files, nil, err) }
+	})
+}
+
+// reset clears the list until the files that are read in the background can be shown.
+func (f *FileChooser) reset(p string, recent bool) {
+	f.path = p
+	f.location.Text = p
+	if recent {
+		f.location.Text = ""
 	}
-	files, err := dir.Readdir(0)
-	if err != nil {
-		return
+	f.recent = recent
+	f.err = nil
+	f.entries, f.names = nil, nil
+	f.files.Items = nil
+	f.suggestDir, f.suggestFiles = "", nil
+	f.updatePlaces()
+}
+
+// loaded shows the files read in the background.
+func (f *FileChooser) loaded(files []fs.FileInfo, names []string, err error) {
+	f.entries, f.names, f.err = files, names, err
+	if f.entries == nil {
+		f.entries = []fs.FileInfo{}
+	}
+	f.refresh()
+}
+
+// loading returns true while a directory is read.
+func (f *FileChooser) loading() bool {
+	return f.entries == nil && f.err == nil
+}
+
+// poll applies the results of the background tasks.
+func (f *FileChooser) poll(state *ui.State) {
+	loading := f.load.poll(state)
+	checking := f.check.poll(state)
+	lookup := f.lookup.poll(state)
+	reading := f.bookmarksLoad.poll(state)
+	writing := f.bookmarksSave.poll(state)
+	if (loading || checking || lookup || reading || writing) && state.Waker() == nil {
+		// the backend can not be woken up when a task is done
+		state.RequestAnimation()
 	}
+}
+
+// refresh shows the directory again, after the filter or the mode has changed.
+func (f *FileChooser) refresh() {
+	f.show(f.entries, f.names...)
+}
+
+// showRecent shows the recently used files.
+func (f *FileChooser) showRecent() {
+	f.reset(f.path, true)
+	fsys, paths := f.fs, append([]string(nil), recentFiles...)
+	f.load.start(func() func(*ui.State) {
+		var files []fs.FileInfo
+		var names []string
+		for _, p := range paths {
+			if info, err := fsys.stat(p); err == nil {
+				files = append(files, info)
+				names = append(names, p)
+			}
+		}
+		return func(*ui.State) { f.loaded(files, names, nil) }
+	})
+}
+
+// show shows a list of files. The files are named by their base name, unless names are given.
+func (f *FileChooser) show(files []fs.FileInfo, names ...string) {
 	f.files.Items = nil
-	for _, i := range files {
-		name := i.Name()
-		i.Mode()
-		if !strings.HasPrefix(name, ".") {
-			icon := "file"
-			if i.IsDir() {
-				icon = "folder"
+	f.files.Selected = 0
+	f.infos = make(map[string]fs.FileInfo)
+	filter := f.SelectedFilter()
+	for i, info := range files {
+		name := info.Name()
+		if strings.HasPrefix(name, ".") && !f.hidden.Checked {
+			continue
+		}
+		if i < len(names) {
+			name = names[i]
+		}
+		item := ListItem{Text: name, Icon: "folder"}
+		size := ""
+		if !info.IsDir() {
+			if f.dirs || !filter.Match(info.Name()) {
+				continue
 			}
-			f.files.Items = append(f.files.Items, ListItem{Text: name, Icon: icon})
+			item.Icon = fileIcon(name, info.Mode())
+			size = formatSize(info.Size())
 		}
+		item.Details = []string{size, info.ModTime().Format("2006-01-02 15:04"), fileType(item.Icon, name)}
+		f.files.Items = append(f.files.Items, item)
+		f.infos[name] = info
+	}
+	f.sort()
+	f.files.Selected = 0
+	if len(f.files.Items) > 0 && f.files.MultiSelect {
+		f.files.Items[0].Selected = true
+	}
+	f.updatePlaces()
+	if f.dirs {
+		f.nameField.Text = ""
+	} else if len(f.files.Items) > 0 && !f.nameField.Editable {
+		f.nameField.Text = (f.files.Items[0].Text)
 	}
-	sort.Slice(f.files.Items, func(i, j int) bool {
-		if (f.files.Items[i].Icon == "folder") != (f.files.Items[j].Icon == "folder") {
-			return f.files.Items[i].Icon == "folder"
+	if f.selectName != "" {
+		for i := range f.files.Items {
+			item := &f.files.Items[i]
+			item.Selected = item.Text == f.selectName
+			if item.Selected {
+				f.files.Selected = i
+				f.nameField.Text = item.Text
+			}
 		}
-		return f.files.Items[i].Text < f.files.Items[j].Text
+		f.selectName = ""
+	}
+}
+
+// sort sorts the files, keeping the selection.
+func (f *FileChooser) sort() {
+	items := f.files.Items
+	sel := ""
+	if f.files.Selected < len(items) {
+		sel = items[f.files.Selected].Text
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if (items[i].Icon == "folder") != (items[j].Icon == "folder") {
+			return items[i].Icon == "folder"
+		}
+		a, b := f.infos[items[i].Text], f.infos[items[j].Text]
+		less := items[i].Text < items[j].Text
+		switch f.sortBy {
+		case ColumnSize:
+			if a.Size() != b.Size() {
+				less = a.Size() < b.Size()
+			}
+		case ColumnModified:
+			if !a.ModTime().Equal(b.ModTime()) {
+				less = a.ModTime().Before(b.ModTime())
+			}
+		case ColumnType:
+			if ta, tb := items[i].Details[2], items[j].Details[2]; ta != tb {
+				less = ta < tb
+			}
+		}
+		return less != f.sortDesc
 	})
-	if len(f.files.Items) > 0 {
-		f.nameField.Text = (f.files.Items[0].Text)
+	for i := range items {
+		if items[i].Text == sel {
+			f.files.Selected = i
+		}
 	}
-	f.path = p
 }
 
 func (f *FileChooser) enter() {
-	sel := f.files.Items[f.files.Selected].Text
-	if strings.HasSuffix(sel, string(filepath.Separator)) {
-		f.set(sel)
-	} else {
-		f.set(filepath.Join(f.path, sel))
+	f.set(f.itemPath(f.files.Items[f.files.Selected].Text))
+}
+
+// itemPath returns the path of a file in the list, the recently used files are listed with their full path.
+func (f *FileChooser) itemPath(name string) string {
+	if f.recent || f.fs.isAbs(name) {
+		return name
+	}
+	return f.fs.join(f.path, name)
+}
+
+// navigate handles a path typed into the location field.
+// Directories are opened, for files the containing directory is opened and the file is selected.
+func (f *FileChooser) navigate(p string) {
+	if !f.fs.isAbs(p) {
+		p = f.fs.join(f.path, p)
+	}
+	p = f.fs.abs(p)
+	f.reset(p, false)
+	fsys := f.fs
+	f.load.start(func() func(*ui.State) {
+		if info, err := fsys.stat(p); err == nil && !info.IsDir() {
+			dir, name := fsys.split(p)
+			dir = fsys.abs(dir)
+			files, err := fsys.readDir(dir)
+			return func(*ui.State) {
+				f.reset(dir, false)
+				f.selectName = name
+				f.loaded(files, nil, err)
+			}
+		}
+		files, err := fsys.readDir(p)
+		return func(*ui.State) { f.loaded(files, nil, err) }
+	})
+}
+
+// complete returns the directory entries that start with the last element of a path.
+// The directory is read in the background, until it has been read no entries are returned.
+func (f *FileChooser) complete(p string) []ListItem {
+	sep := f.fs.separator()
+	// only the part before the last separator is a directory, the rest is the prefix of a name in that directory
+	dp, prefix := f.fs.split(p)
+	dir := dp
+	if dir == "" {
+		dir = f.path
+	} else if !f.fs.isAbs(dir) {
+		dir = f.fs.join(f.path, dir)
+	}
+	if dir != f.suggestDir {
+		f.suggestDir, f.suggestFiles = dir, nil
+		fsys := f.fs
+		f.lookup.start(func() func(*ui.State) {
+			files, _ := fsys.readDir(dir)
+			sort.Slice(files, func(i, j int) bool { return files[i].Name() < files[j].Name() })
+			if files == nil {
+				files = []fs.FileInfo{}
+			}
+			return func(*ui.State) {
+				if f.suggestDir == dir {
+					f.suggestFiles = files
+					// show the suggestions again, now that they are available
+					f.location.edited = true
+				}
+			}
+		})
+	}
+	files := f.suggestFiles
+	if files == nil {
+		return nil
+	}
+	var items []ListItem
+	if prefix != "" && strings.HasPrefix("..", prefix) {
+		// the parent directory is not listed by readDir
+		items = append(items, ListItem{Icon: "folder", Text: dp + ".." + sep})
+	}
+	for _, info := range files {
+		name := info.Name()
+		if !strings.HasPrefix(name, prefix) || strings.HasPrefix(name, ".") && !strings.HasPrefix(prefix, ".") {
+			continue
+		}
+		if info.IsDir() {
+			items = append(items, ListItem{Icon: "folder", Text: dp + name + sep})
+		} else {
+			items = append(items, ListItem{Icon: fileIcon(name, info.Mode()), Text: dp + name})
+		}
+	}
+	return items
+}
+
+var fileIcons = map[string]string{}
+
+func init() {
+	for icon, exts := range map[string]string{
+		"file.text":    ".txt .md .go .c .h .cpp .hpp .rs .java .py .js .ts .html .css .json .xml .yaml .yml .toml .ini .cfg .conf .csv .log .sh .bat",
+		"file.image":   ".png .jpg .jpeg .gif .bmp .svg .webp .tif .tiff .ico",
+		"file.audio":   ".wav .mp3 .ogg .oga .flac .aac .m4a .opus .mid .midi",
+		"file.video":   ".mp4 .m4v .mkv .avi .mov .webm .wmv .ogv .mpg .mpeg",
+		"file.archive": ".zip .tar .gz .tgz .bz2 .xz .txz .zst .7z .rar .jar",
+	} {
+		for _, ext := range strings.Fields(exts) {
+			fileIcons[ext] = icon
+		}
+	}
+}
+
+// fileIcon returns the icon for a file based on its extension.
+func fileIcon(name string, mode fs.FileMode) string {
+	if icon, ok := fileIcons[strings.ToLower(filepath.Ext(name))]; ok {
+		return icon
+	}
+	if mode&0111 != 0 {
+		return "file.exec"
+	}
+	return "file"
+}
+
+// fileType returns a description of a file's type for the details view.
+func fileType(icon, name string) string {
+	switch icon {
+	case "folder":
+		return "Folder"
+	case "file.text":
+		return "Text"
+	case "file.image":
+		return "Image"
+	case "file.audio":
+		return "Audio"
+	case "file.video":
+		return "Video"
+	case "file.archive":
+		return "Archive"
+	case "file.exec":
+		return "Program"
+	}
+	if ext := filepath.Ext(name); ext != "" {
+		return strings.ToUpper(ext[1:])
+	}
+	return "File"
+}
+
+// formatSize returns a human readable file size.
+func formatSize(size int64) string {
+	if size < 1000 {
+		return strconv.FormatInt(size, 10) + " B"
+	}
+	s := float64(size)
+	for _, unit := range []string{"kB", "MB", "GB", "TB"} {
+		s /= 1000
+		// values that round up to 1000.0 are shown in the next unit
+		if s < 999.95 || unit == "TB" {
+			return strconv.FormatFloat(s, 'f', 1, 64) + " " + unit
+		}
+	}
+	return ""
+}
+
+// fileList shows the list of files, or an error if the directory could not be read.
+type fileList struct {
+	f    *FileChooser
+	list *ScrollView
+}
+
+func (l *fileList) SetTheme(theme *Theme) {
+	SetTheme(l.list, theme)
+}
+
+func (l *fileList) PreferredSize(fonts draw.FontLookup) (int, int) {
+	return l.list.PreferredSize(fonts)
+}
+
+func (l *fileList) Update(g *draw.Buffer, state *ui.State) {
+	w, h := g.Size()
+	y := 0
+	if l.f.loading() {
+		theme := l.f.files.Theme
+		th := g.FontLookup.Metrics(theme.Font("text")).LineHeight()
+		l.f.errText.DrawLeft(g, draw.XYWH(5, 5, w-10, th), "Loading...", theme.Font("text"), theme.Color("textDisabled"))
+		g.Fill(draw.XYWH(5, th+10, w-10, 4), theme.Color("veil"))
+		drawIndeterminate(g, draw.XYWH(5, th+10, w-10, 4), &l.f.loadAnim, state, theme.Color("selection"))
+		return
+	}
+	if l.f.err != nil {
+		theme := l.f.files.Theme
+		_, th := l.f.errText.Size(l.f.err.Error(), theme.Font("text"), g.FontLookup)
+		l.f.errText.DrawLeftIcon(g, draw.XYWH(5, 5, w-10, th), l.f.err.Error(), theme.Font("text"), theme.Color("inputError"), "warning", 3)
+		y = th + 10
+	}
+	if l.f.details {
+		l.f.files.Columns = l.f.header.columns(g.FontLookup)
+		_, hh := l.f.header.PreferredSize(g.FontLookup)
+		// the header has to line up with the list, which is narrower if the scroll bar is shown
+		hw := w
+		if _, lh := l.f.files.PreferredSize(g.FontLookup); lh > h-y-hh {
+			hw -= 15
+		}
+		state.UpdateChild(g, draw.XYWH(0, y, hw, hh), &l.f.header)
+		y += hh
+	}
+	state.UpdateChild(g, draw.XYXY(0, y, w, h), l.list)
+}
+
+// fileHeader shows the column names of the details view, clicking a column sorts the files.
+type fileHeader struct {
+	f     *FileChooser
+	texts [4]text.Text
+}
+
+var fileColumnNames = [...]string{"Name", "Size", "Modified", "Type"}
+
+// columns returns the widths of the size, modification time and type columns.
+func (h *fileHeader) columns(fonts draw.FontLookup) []int {
+	m := fonts.Metrics(h.f.files.Theme.Font("text"))
+	return []int{int(m.Advance("000.0 MB")) + 15, int(m.Advance("0000-00-00 00:00")) + 15, int(m.Advance("Program")) + 25}
+}
+
+func (h *fileHeader) PreferredSize(fonts draw.FontLookup) (int, int) {
+	return 0, fonts.Metrics(h.f.files.Theme.Font("text")).LineHeight() + 6
+}
+
+func (h *fileHeader) Update(g *draw.Buffer, state *ui.State) {
+	w, hh := g.Size()
+	theme := h.f.files.Theme
+	g.Fill(draw.WH(w, hh), theme.Color("altBackground"))
+	g.Fill(draw.XYWH(0, hh-1, w, 1), theme.Color("separator"))
+	cols := h.columns(g.FontLookup)
+	x := w - 2
+	for _, c := range cols {
+		x -= c
+	}
+	rects := []image.Rectangle{draw.XYXY(0, 0, x, hh)}
+	for _, c := range cols {
+		rects = append(rects, draw.XYWH(x, 0, c, hh))
+		x += c
+	}
+	mouse := state.MousePos()
+	for i, r := range rects {
+		col := FileColumn(i)
+		if state.IsHovered() && mouse.In(r) {
+			g.Fill(r, theme.Color("buttonHovered"))
+			if state.MouseClick(ui.MouseLeft) {
+				if h.f.sortBy == col {
+					h.f.SetSort(col, !h.f.sortDesc)
+				} else {
+					// the most recent files are usually the interesting ones
+					h.f.SetSort(col, col == ColumnModified)
+				}
+				state.RequestUpdate()
+			}
+		}
+		if i > 0 {
+			g.Fill(draw.XYWH(r.Min.X, 3, 1, hh-6), theme.Color("separator"))
+		}
+		h.texts[i].DrawLeft(g, draw.XYXY(r.Min.X+5, 0, r.Max.X, hh), fileColumnNames[i], theme.Font("text"), theme.Color("text"))
+		if h.f.sortBy == col {
+			icon := "up.arrow"
+			if h.f.sortDesc {
+				icon = "down.arrow"
+			}
+			is := hh - 8
+			g.Icon(draw.XYWH(r.Max.X-is-3, 4, is, is), icon, theme.Color("textDisabled"))
+		}
+	}
+}
+
+// fileOptional shows a component only if a condition is met.
+type fileOptional struct {
+	show func() bool
+	c    ui.Component
+}
+
+func (o *fileOptional) SetTheme(theme *Theme) {
+	SetTheme(o.c, theme)
+}
+
+func (o *fileOptional) PreferredSize(fonts draw.FontLookup) (int, int) {
+	if !o.show() {
+		return 0, 0
+	}
+	return o.c.PreferredSize(fonts)
+}
+
+func (o *fileOptional) Update(g *draw.Buffer, state *ui.State) {
+	if o.show() {
+		state.UpdateChild(g, draw.WH(g.Size()), o.c)
+	}
+}
+
+// breadcrumbs shows the parent directories of the current path, clicking one of them opens it.
+type breadcrumbs struct {
+	f     *FileChooser
+	parts []text.Text
+}
+
+func (b *breadcrumbs) PreferredSize(fonts draw.FontLookup) (int, int) {
+	return 0, fonts.Metrics(b.f.files.Theme.Font("text")).LineHeight() + 10
+}
+
+func (b *breadcrumbs) Update(g *draw.Buffer, state *ui.State) {
+	w, h := g.Size()
+	theme := b.f.files.Theme
+	font := theme.Font("text")
+	m := g.FontLookup.Metrics(font)
+	if b.f.recent {
+		if len(b.parts) == 0 {
+			b.parts = make([]text.Text, 1)
+		}
+		b.parts[0].DrawLeft(g, draw.XYXY(5, 0, w, h), "Recent", font, theme.Color("text"))
+		return
+	}
+	var dirs []string
+	for p := b.f.path; ; {
+		dirs = append(dirs, p)
+		parent := b.f.fs.dir(p)
+		if parent == p {
+			break
+		}
+		p = parent
+	}
+	if len(b.parts) < len(dirs) {
+		b.parts = make([]text.Text, len(dirs))
+	}
+	sep := " " + b.f.fs.separator() + " "
+	sw := int(m.Advance(sep))
+	// show as many directories as possible, starting with the current one
+	x := w
+	first := 0
+	for i, d := range dirs {
+		dw := int(m.Advance(b.f.fs.base(d))) + sw
+		if x-dw < 0 && i > 0 {
+			break
+		}
+		x -= dw
+		first = i
+	}
+	x = 5
+	mouse := state.MousePos()
+	for i := first; i >= 0; i-- {
+		name := b.f.fs.base(dirs[i])
+		nw := int(m.Advance(name))
+		r := draw.XYWH(x, 0, nw, h)
+		color := theme.Color("text")
+		if i > 0 && state.IsHovered() && mouse.In(r) {
+			g.Fill(r, theme.Color("buttonHovered"))
+			if state.MouseClick(ui.MouseLeft) {
+				b.f.set(dirs[i])
+				state.RequestUpdate()
+			}
+		}
+		b.parts[i].DrawLeft(g, r, name, font, color)
+		x += nw
+		if i > 0 {
+			g.Text(image.Pt(x, (h+m.Ascent()-m.Descent())/2), sep, theme.Color("textDisabled"), font)
+			x += sw
+		}
 	}
 }
@@ -3,11 +3,13 @@
 package toolkit
 
 import (
-	"path/filepath"
-
 	"github.com/jfreymuth/ui"
 )
 
 func (f *FileChooser) back(*ui.State) {
-	f.set(filepath.Dir(f.path))
+	if f.recent {
+		f.set(f.path)
+		return
+	}
+	f.set(f.fs.dir(f.path))
 }
@@ -0,0 +1,116 @@
+package toolkit
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/jfreymuth/ui"
+)
+
+func TestMain(m *testing.M) {
+	// the tests must not read or change the user's bookmarks
+	BookmarksFile = ""
+	os.Exit(m.Run())
+}
+
+func TestFileFilterMatch(t *testing.T) {
+	tests := []struct {
+		patterns []string
+		name     string
+		want     bool
+	}{
+		{nil, "anything", true},
+		{[]string{"*.go"}, "main.go", true},
+		{[]string{"*.go"}, "MAIN.GO", true},
+		{[]string{"*.GO"}, "main.go", true},
+		{[]string{"*.go"}, "main.go.txt", false},
+		{[]string{"*.png", "*.jpg"}, "a.jpg", true},
+		{[]string{"*.png", "*.jpg"}, "a.gif", false},
+		{[]string{"Makefile"}, "makefile", true},
+		{[]string{"[invalid"}, "[invalid", false},
+	}
+	for _, tt := range tests {
+		if got := (FileFilter{Patterns: tt.patterns}).Match(tt.name); got != tt.want {
+			t.Errorf("%q.Match(%q) = %v, want %v", tt.patterns, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFileChooserComplete(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a/b.txt":      {},
+		"a/c/d.txt":    {},
+		"dir/e.go":     {},
+		"dir/f.go":     {},
+		"file.txt":     {},
+		".hidden":      {},
+		"..two":        {},
+		"dir/.config":  {},
+		"dir/sub/x.go": {},
+	}
+	f := NewFileChooser()
+	f.SetFS(fsys)
+	f.SetPath("/dir")
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"", []string{"e.go", "f.go", "sub/"}},
+		{".", []string{"../", ".config"}},
+		{"..", []string{"../"}},
+		{"../", []string{"../a/", "../dir/", "../file.txt"}},
+		{"../.", []string{"../../", "../..two", "../.hidden"}},
+		{"../a/..", []string{"../a/../"}},
+		{"../a/../fi", []string{"../a/../file.txt"}},
+		{"sub/", []string{"sub/x.go"}},
+		{"su", []string{"sub/"}},
+		{"/dir/", []string{"/dir/e.go", "/dir/f.go", "/dir/sub/"}},
+		{"/a/c/", []string{"/a/c/d.txt"}},
+		{"missing/", nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, item := range completeWait(f, tt.in) {
+			got = append(got, item.Text)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("complete(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	tests := []struct {
+		size int64
+		want string
+	}{
+		{0, "0 B"},
+		{999, "999 B"},
+		{1000, "1.0 kB"},
+		{1234, "1.2 kB"},
+		{999949, "999.9 kB"},
+		{999950, "1.0 MB"},
+		{1500000, "1.5 MB"},
+		{2000000000, "2.0 GB"},
+		{3100000000000, "3.1 TB"},
+		{5000000000000000, "5000.0 TB"},
+	}
+	for _, tt := range tests {
+		if got := formatSize(tt.size); got != tt.want {
+			t.Errorf("formatSize(%d) = %q, want %q", tt.size, got, tt.want)
+		}
+	}
+}
+
+// completeWait calls complete again after the directory has been read in the background.
+func completeWait(f *FileChooser, p string) []ListItem {
+	var st ui.BackendState
+	f.complete(p)
+	for f.lookup.poll(&st.State) {
+		time.Sleep(time.Millisecond)
+	}
+	return f.complete(p)
+}
@@ -0,0 +1,207 @@
+package toolkit
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/jfreymuth/ui"
+)
+
+// BookmarksFile is the file the bookmarks of the FileChooser's sidebar are stored in, one path per line.
+// It is read in the background when the first file chooser is created.
+// If it is empty, bookmarks are only kept until the program exits.
+var BookmarksFile = defaultBookmarksFile()
+
+func defaultBookmarksFile() string {
+	if dir, err := os.UserConfigDir(); err == nil {
+		return filepath.Join(dir, "jfreymuth-ui", "bookmarks")
+	}
+	return ""
+}
+
+var (
+	bookmarks       []string
+	bookmarksLoaded bool
+	bookmarksGen    int        // incremented whenever the bookmarks are saved
+	bookmarksMu     sync.Mutex // serializes writing the bookmarks file
+	bookmarksSaved  int        // the generation that was written last, guarded by bookmarksMu
+	// recentFiles are the paths chosen in any FileChooser, the most recent first.
+	recentFiles []string
+)
+
+const maxRecentFiles = 20
+
+func addRecent(paths ...string) {
+	for _, p := range paths {
+		for i, r := range recentFiles {
+			if r == p {
+				recentFiles = append(recentFiles[:i], recentFiles[i+1:]...)
+				break
+			}
+		}
+		recentFiles = append([]string{p}, recentFiles...)
+	}
+	if len(recentFiles) > maxRecentFiles {
+		recentFiles = recentFiles[:maxRecentFiles]
+	}
+}
+
+// loadBookmarks starts reading the bookmarks file, unless it has already been read.
+func (f *FileChooser) loadBookmarks() {
+	if bookmarksLoaded || f.readingBookmarks {
+		return
+	}
+	if BookmarksFile == "" {
+		bookmarksLoaded = true
+		return
+	}
+	f.readingBookmarks = true
+	file := BookmarksFile
+	f.bookmarksLoad.start(func() func(*ui.State) {
+		var loaded []string
+		// if the file can not be read, most likely nothing has been bookmarked yet
+		data, _ := ioutil.ReadFile(file)
+		for _, line := range strings.Split(string(data), "\n") {
+			if line = strings.TrimRight(line, "\r"); line != "" {
+				loaded = append(loaded, line)
+			}
+		}
+		return func(*ui.State) {
+			f.readingBookmarks = false
+			if bookmarksLoaded {
+				// another file chooser was faster
+				f.updatePlaces()
+				return
+			}
+			// bookmarks added before the file was read are kept
+			added := false
+			for _, b := range bookmarks {
+				if !containsString(loaded, b) {
+					loaded = append(loaded, b)
+					added = true
+				}
+			}
+			bookmarks, bookmarksLoaded = loaded, true
+			if added {
+				f.saveBookmarks()
+			}
+			f.updatePlaces()
+		}
+	})
+}
+
+// saveBookmarks writes the bookmarks file in the background. It is only written once it has been read.
+func (f *FileChooser) saveBookmarks() {
+	if BookmarksFile == "" || !bookmarksLoaded {
+		return
+	}
+	bookmarksGen++
+	file, gen := BookmarksFile, bookmarksGen
+	var data []byte
+	for _, b := range bookmarks {
+		data = append(data, b+"\n"...)
+	}
+	f.bookmarksSave.start(func() func(*ui.State) {
+		bookmarksMu.Lock()
+		defer bookmarksMu.Unlock()
+		if gen < bookmarksSaved {
+			// a newer list has already been written
+			return nil
+		}
+		bookmarksSaved = gen
+		err := os.MkdirAll(filepath.Dir(file), 0755)
+		if err == nil {
+			err = ioutil.WriteFile(file, data, 0644)
+		}
+		return func(*ui.State) { f.err = err }
+	})
+}
+
+func containsString(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
+
+// AddBookmark adds a directory to the sidebar of all file choosers that show the OS file system.
+func (f *FileChooser) AddBookmark(path string) {
+	if !f.isOS() || containsString(bookmarks, path) {
+		return
+	}
+	bookmarks = append(bookmarks, path)
+	f.saveBookmarks()
+	f.updatePlaces()
+}
+
+// RemoveBookmark removes a directory from the sidebar of all file choosers.
+func (f *FileChooser) RemoveBookmark(path string) {
+	for i, b := range bookmarks {
+		if b == path {
+			bookmarks = append(bookmarks[:i], bookmarks[i+1:]...)
+			f.saveBookmarks()
+			f.updatePlaces()
+			return
+		}
+	}
+}
+
+func (f *FileChooser) initPlaces() {
+	f.places = NewList()
+	f.places.Changed = func(*ui.State, ListItem) { f.openPlace(f.places.Selected) }
+	menu := f.places.ContextMenu()
+	menu.AddSeparator()
+	f.unbookmark = menu.AddItem("&Remove Bookmark", func(*ui.State) {
+		if i := f.places.Selected; i >= 0 && i < len(f.placePaths) {
+			f.RemoveBookmark(f.placePaths[i])
+		}
+	})
+}
+
+// updatePlaces fills the sidebar, and selects the place that is currently shown.
+func (f *FileChooser) updatePlaces() {
+	f.places.Items = f.places.Items[:0]
+	f.placePaths = f.placePaths[:0]
+	add := func(icon, name, path string) {
+		f.places.Items = append(f.places.Items, ListItem{Icon: icon, Text: name})
+		f.placePaths = append(f.placePaths, path)
+	}
+	if !f.isOS() {
+		add("folder", "/", "/")
+	} else {
+		if home, err := os.UserHomeDir(); err == nil {
+			add("home", "Home", home)
+		}
+		if wd, err := os.Getwd(); err == nil {
+			add("folder", f.fs.base(wd), wd)
+		}
+		add("history", "Recent", "")
+		f.loadBookmarks()
+		for _, b := range bookmarks {
+			add("bookmark", f.fs.base(b), b)
+		}
+	}
+	f.places.Selected = -1
+	for i, p := range f.placePaths {
+		if f.recent && p == "" || !f.recent && p == f.path {
+			f.places.Selected = i
+		}
+	}
+}
+
+func (f *FileChooser) openPlace(i int) {
+	if i < 0 || i >= len(f.placePaths) {
+		return
+	}
+	f.unbookmark.Disabled = f.places.Items[i].Icon != "bookmark"
+	if p := f.placePaths[i]; p == "" {
+		f.showRecent()
+	} else {
+		f.set(p)
+	}
+}
@@ -0,0 +1,56 @@
+package toolkit
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jfreymuth/ui"
+)
+
+func TestBookmarks(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "dir", "bookmarks")
+	BookmarksFile, bookmarks, bookmarksLoaded = file, nil, false
+	defer func() { BookmarksFile, bookmarks, bookmarksLoaded = "", nil, false }()
+
+	var st ui.BackendState
+	wait := func(f *FileChooser) {
+		for i := 0; i < 1000 && (f.readingBookmarks || f.bookmarksSave.poll(&st.State)); i++ {
+			time.Sleep(time.Millisecond)
+			f.poll(&st.State)
+		}
+	}
+	f := NewFileChooser()
+	// bookmarks added before the file has been read are kept
+	f.AddBookmark("/a")
+	wait(f)
+	if !bookmarksLoaded || !reflect.DeepEqual(bookmarks, []string{"/a"}) {
+		t.Fatal(bookmarksLoaded, bookmarks)
+	}
+	f.AddBookmark("/b")
+	f.AddBookmark("/a")
+	f.RemoveBookmark("/a")
+	f.AddBookmark("/c")
+	wait(f)
+	if data, err := ioutil.ReadFile(file); err != nil || string(data) != "/b\n/c\n" {
+		t.Errorf("%q %v", data, err)
+	}
+
+	bookmarks, bookmarksLoaded = nil, false
+	f = NewFileChooser()
+	wait(f)
+	if !reflect.DeepEqual(bookmarks, []string{"/b", "/c"}) {
+		t.Error(bookmarks)
+	}
+	var places []string
+	for _, item := range f.places.Items {
+		if item.Icon == "bookmark" {
+			places = append(places, item.Text)
+		}
+	}
+	if !reflect.DeepEqual(places, []string{"b", "c"}) {
+		t.Error(places)
+	}
+}
@@ -0,0 +1,274 @@
+package toolkit
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif" // decoders for the preview
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/jfreymuth/ui"
+	"github.com/jfreymuth/ui/draw"
+	"github.com/jfreymuth/ui/text"
+)
+
+const (
+	thumbnailSize = 192
+	// images larger than this are not decoded, only their size is shown
+	maxPreviewPixels = 4096 * 4096
+	previewLines     = 30
+	// the maximum number of bytes used by the previews of a FileChooser
+	previewCacheSize = 32 << 20
+	// the memory used by a preview in addition to its contents, so that empty previews are evicted too
+	previewEntryCost = 1024
+)
+
+// filePreview shows a thumbnail of the selected image, or the first lines of the selected text file.
+type filePreview struct {
+	f     *FileChooser
+	cache previewCache
+	texts []text.Text
+	anim  float32
+}
+
+// A preview is created in the background, and shown once done is set.
+// Previews that are not done are not stored in the cache.
+type preview struct {
+	done     bool
+	thumb    *image.RGBA
+	size     image.Point // the size of the original image
+	lines    []string
+	lastUsed uint
+}
+
+// previewCache keeps the most recently used previews, up to a total size in bytes.
+// Previews are loaded one at a time by a single worker goroutine, which only loads the most recently requested one.
+type previewCache struct {
+	mu      sync.Mutex
+	entries map[string]*preview
+	size    int
+	max     int
+	clock   uint
+	want    string // the key of the preview that is loaded next
+	load    func() *preview
+	current string // the key of the preview that is being loaded
+	working bool
+}
+
+// get returns a cached preview. If it is not cached, it is loaded in the background and a preview that is not done is returned.
+// A request replaces any previous request that has not been started yet.
+func (c *previewCache) get(key string, load func() *preview) *preview {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clock++
+	if p, ok := c.entries[key]; ok {
+		p.lastUsed = c.clock
+		return p
+	}
+	if c.working && key == c.current {
+		c.want, c.load = "", nil
+	} else {
+		c.want, c.load = key, load
+	}
+	if !c.working && c.load != nil {
+		c.working = true
+		go c.work()
+	}
+	return &preview{}
+}
+
+// work loads the requested previews until there are no more requests.
+func (c *previewCache) work() {
+	for {
+		c.mu.Lock()
+		key, load := c.want, c.load
+		c.want, c.load, c.current = "", nil, key
+		if load == nil {
+			c.working = false
+			c.mu.Unlock()
+			return
+		}
+		c.mu.Unlock()
+		p := load()
+		c.mu.Lock()
+		if c.entries == nil {
+			c.entries = make(map[string]*preview)
+		}
+		c.clock++
+		p.done, p.lastUsed = true, c.clock
+		c.entries[key] = p
+		c.size += p.bytes()
+		c.evict()
+		c.mu.Unlock()
+	}
+}
+
+// evict removes the least recently used previews until the cache is small enough.
+func (c *previewCache) evict() {
+	for c.size > c.max {
+		var oldest string
+		var op *preview
+		for k, p := range c.entries {
+			if op == nil || p.lastUsed < op.lastUsed {
+				oldest, op = k, p
+			}
+		}
+		if op == nil || op.lastUsed == c.clock {
+			return
+		}
+		c.size -= op.bytes()
+		delete(c.entries, oldest)
+	}
+}
+
+func (p *preview) bytes() int {
+	n := previewEntryCost
+	if p.thumb != nil {
+		n += len(p.thumb.Pix)
+	}
+	for _, l := range p.lines {
+		n += len(l)
+	}
+	return n
+}
+
+// loadPreview reads a file and creates a preview, it is called on a separate goroutine.
+func loadPreview(fsys fileSystem, path string) *preview {
+	p := &preview{}
+	file, err := fsys.open(path)
+	if err != nil {
+		return p
+	}
+	defer file.Close()
+	head := make([]byte, 4096)
+	n, _ := io.ReadFull(file, head)
+	head = head[:n]
+	r := io.MultiReader(bytes.NewReader(head), file)
+	if cfg, _, err := image.DecodeConfig(bytes.NewReader(head)); err == nil {
+		p.size = image.Pt(cfg.Width, cfg.Height)
+		if cfg.Width*cfg.Height <= maxPreviewPixels {
+			if img, _, err := image.Decode(r); err == nil {
+				p.thumb = thumbnail(img)
+			}
+		}
+		return p
+	}
+	if bytes.IndexByte(head, 0) < 0 {
+		// if the file was cut off in the middle of a character, it is still text
+		for i := 0; i < 3 && len(head) > 0 && !utf8.Valid(head); i++ {
+			head = head[:len(head)-1]
+		}
+		if utf8.Valid(head) {
+			lines := strings.Split(strings.Replace(string(head), "\t", "    ", -1), "\n")
+			if len(lines) > previewLines {
+				lines = lines[:previewLines]
+			}
+			for i := range lines {
+				lines[i] = strings.TrimRight(lines[i], "\r")
+			}
+			p.lines = lines
+		}
+	}
+	return p
+}
+
+// thumbnail scales an image down to fit into a square of thumbnailSize, by averaging samples of each pixel's area.
+func thumbnail(img image.Image) *image.RGBA {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w > thumbnailSize || h > thumbnailSize {
+		if w > h {
+			w, h = thumbnailSize, h*thumbnailSize/w
+		} else {
+			w, h = w*thumbnailSize/h, thumbnailSize
+		}
+	}
+	if w < 1 {
+		w = 1
+	}
+	if h < 1 {
+		h = 1
+	}
+	const samples = 4
+	thumb := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var r, g, bl, a uint32
+			for sy := 0; sy < samples; sy++ {
+				for sx := 0; sx < samples; sx++ {
+					px := b.Min.X + (x*samples+sx)*b.Dx()/(w*samples)
+					py := b.Min.Y + (y*samples+sy)*b.Dy()/(h*samples)
+					cr, cg, cb, ca := img.At(px, py).RGBA()
+					r, g, bl, a = r+cr, g+cg, bl+cb, a+ca
+				}
+			}
+			i := thumb.PixOffset(x, y)
+			const div = samples * samples * 257
+			thumb.Pix[i], thumb.Pix[i+1], thumb.Pix[i+2], thumb.Pix[i+3] = uint8(r/div), uint8(g/div), uint8(bl/div), uint8(a/div)
+		}
+	}
+	return thumb
+}
+
+func (p *filePreview) PreferredSize(fonts draw.FontLookup) (int, int) {
+	return thumbnailSize + 20, 0
+}
+
+func (p *filePreview) Update(g *draw.Buffer, state *ui.State) {
+	w, h := g.Size()
+	theme := p.f.files.Theme
+	g.Fill(draw.XYWH(0, 0, 1, h), theme.Color("separator"))
+	l := p.f.files
+	if l.Selected < 0 || l.Selected >= len(l.Items) || l.Items[l.Selected].Icon == "folder" {
+		return
+	}
+	name := l.Items[l.Selected].Text
+	info, ok := p.f.infos[name]
+	if !ok {
+		return
+	}
+	path := p.f.itemPath(name)
+	fsys := p.f.fs
+	key := fmt.Sprint(path, "\x00", info.Size(), "\x00", info.ModTime().UnixNano())
+	pr := p.cache.get(key, func() *preview { return loadPreview(fsys, path) })
+	p.cache.mu.Lock()
+	done, thumb, size, lines := pr.done, pr.thumb, pr.size, pr.lines
+	p.cache.mu.Unlock()
+
+	font := theme.Font("text")
+	lh := g.FontLookup.Metrics(font).LineHeight()
+	if len(p.texts) < previewLines+2 {
+		p.texts = make([]text.Text, previewLines+2)
+	}
+	x, y := 10, 10
+	switch {
+	case !done:
+		p.texts[0].DrawLeft(g, draw.XYWH(x, y, w-x, lh), "Loading...", font, theme.Color("textDisabled"))
+		drawIndeterminate(g, draw.XYWH(x, y+lh+5, w-20, 4), &p.anim, state, theme.Color("selection"))
+	case size != image.Point{}:
+		if thumb != nil {
+			tw, th := thumb.Rect.Dx(), thumb.Rect.Dy()
+			r := draw.XYWH(x+(w-20-tw)/2, y+(thumbnailSize-th)/2, tw, th)
+			g.Outline(r.Inset(-1), theme.Color("separator"))
+			g.Image(r, thumb, draw.White, false)
+			y += thumbnailSize + 5
+		}
+		p.texts[0].DrawLeft(g, draw.XYWH(x, y, w-x, lh), fmt.Sprintf("%d × %d", size.X, size.Y), font, theme.Color("text"))
+		p.texts[1].DrawLeft(g, draw.XYWH(x, y+lh, w-x, lh), formatSize(info.Size()), font, theme.Color("textDisabled"))
+	case lines != nil:
+		g.Push(draw.XYXY(x, y, w-5, h-5))
+		for i, line := range lines {
+			p.texts[i].DrawLeft(g, draw.XYWH(0, i*lh, w, lh), line, font, theme.Color("text"))
+		}
+		g.Pop()
+	default:
+		is := thumbnailSize / 2
+		g.Icon(draw.XYWH((w-is)/2, y, is, is), l.Items[l.Selected].Icon, theme.Color("textDisabled"))
+		p.texts[0].DrawCentered(g, draw.XYWH(0, y+is+5, w, lh), formatSize(info.Size()), font, theme.Color("textDisabled"))
+	}
+}
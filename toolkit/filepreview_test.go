@@ -0,0 +1,67 @@
+package toolkit
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// waitPreview requests a preview until it is done.
+func waitPreview(t *testing.T, c *previewCache, key string, load func() *preview) *preview {
+	for i := 0; i < 1000; i++ {
+		if p := c.get(key, load); p.done {
+			return p
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("preview not loaded:", key)
+	return nil
+}
+
+func TestPreviewCacheEvictsEmpty(t *testing.T) {
+	c := previewCache{max: 10 * previewEntryCost}
+	for i := 0; i < 100; i++ {
+		waitPreview(t, &c, strconv.Itoa(i), func() *preview { return &preview{} })
+	}
+	if len(c.entries) > 10 || c.size > c.max {
+		t.Errorf("%d entries, %d bytes", len(c.entries), c.size)
+	}
+	if _, ok := c.entries["99"]; !ok {
+		t.Error("the most recent preview was evicted")
+	}
+}
+
+func TestPreviewCacheDropsRequests(t *testing.T) {
+	c := previewCache{max: previewCacheSize}
+	var mu sync.Mutex
+	var loaded []string
+	started, block := make(chan struct{}), make(chan struct{})
+	load := func(key string) func() *preview {
+		return func() *preview {
+			if key == "first" {
+				close(started)
+				<-block
+			}
+			mu.Lock()
+			loaded = append(loaded, key)
+			mu.Unlock()
+			return &preview{lines: []string{key}}
+		}
+	}
+	c.get("first", load("first"))
+	<-started
+	// the worker is busy, only the last of these requests is loaded afterwards
+	for i := 0; i < 10; i++ {
+		c.get(strconv.Itoa(i), load(strconv.Itoa(i)))
+	}
+	close(block)
+	if p := waitPreview(t, &c, "9", load("9")); p.lines[0] != "9" {
+		t.Error(p.lines)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(loaded) != 2 || loaded[0] != "first" || loaded[1] != "9" {
+		t.Error(loaded)
+	}
+}
@@ -0,0 +1,101 @@
+package toolkit
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// A WritableFS is a file system that can check whether a file can be written.
+// If the file system of a FileChooser implements WritableFS, a file can only be saved if CanWrite returns nil.
+type WritableFS interface {
+	fs.FS
+	CanWrite(name string) error
+}
+
+// fileSystem is the file system shown by a FileChooser.
+// All paths are absolute, relative paths are resolved by abs.
+type fileSystem interface {
+	readDir(dir string) ([]fs.FileInfo, error)
+	stat(name string) (fs.FileInfo, error)
+	open(name string) (fs.File, error)
+	abs(p string) string
+	isAbs(p string) bool
+	join(dir, name string) string
+	split(p string) (dir, name string)
+	dir(p string) string
+	base(p string) string
+	separator() string
+	// name converts a path to the form that is passed to FileChooser.Action.
+	name(p string) string
+}
+
+type osFileSystem struct{}
+
+func (osFileSystem) readDir(dir string) ([]fs.FileInfo, error) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return nil, err
+	}
+	defer d.Close()
+	// if the directory can only be read partially, the entries that could be read are returned anyway
+	return d.Readdir(0)
+}
+
+func (osFileSystem) stat(name string) (fs.FileInfo, error) { return os.Stat(name) }
+func (osFileSystem) open(name string) (fs.File, error)     { return os.Open(name) }
+func (osFileSystem) isAbs(p string) bool                   { return filepath.IsAbs(p) }
+func (osFileSystem) join(dir, name string) string          { return filepath.Join(dir, name) }
+func (osFileSystem) split(p string) (string, string)       { return filepath.Split(p) }
+func (osFileSystem) dir(p string) string                   { return filepath.Dir(p) }
+func (osFileSystem) separator() string                     { return string(filepath.Separator) }
+func (osFileSystem) name(p string) string                  { return p }
+
+func (osFileSystem) abs(p string) string {
+	p, _ = filepath.Abs(p)
+	return p
+}
+
+func (osFileSystem) base(p string) string {
+	if name := filepath.Base(p); name != p && name != "" {
+		return name
+	}
+	return p
+}
+
+// ioFileSystem adapts an fs.FS. Paths start with a slash, which is removed before calling the fs.FS.
+type ioFileSystem struct {
+	fsys fs.FS
+}
+
+func (f ioFileSystem) readDir(dir string) ([]fs.FileInfo, error) {
+	entries, err := fs.ReadDir(f.fsys, f.name(dir))
+	infos := make([]fs.FileInfo, 0, len(entries))
+	for _, e := range entries {
+		if info, ierr := e.Info(); ierr == nil {
+			infos = append(infos, info)
+		} else if err == nil {
+			err = ierr
+		}
+	}
+	return infos, err
+}
+
+func (f ioFileSystem) stat(name string) (fs.FileInfo, error) { return fs.Stat(f.fsys, f.name(name)) }
+func (f ioFileSystem) open(name string) (fs.File, error)     { return f.fsys.Open(f.name(name)) }
+func (ioFileSystem) abs(p string) string                     { return path.Clean("/" + p) }
+func (ioFileSystem) isAbs(p string) bool                     { return strings.HasPrefix(p, "/") }
+func (ioFileSystem) join(dir, name string) string            { return path.Join(dir, name) }
+func (ioFileSystem) split(p string) (string, string)         { return path.Split(p) }
+func (ioFileSystem) dir(p string) string                     { return path.Dir(p) }
+func (ioFileSystem) base(p string) string                    { return path.Base(p) }
+func (ioFileSystem) separator() string                       { return "/" }
+
+func (ioFileSystem) name(p string) string {
+	if p = strings.TrimPrefix(path.Clean("/"+p), "/"); p != "" {
+		return p
+	}
+	return "."
+}
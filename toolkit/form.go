@@ -14,8 +14,10 @@ type Form struct {
 
 type FormField struct {
 	Content ui.Component
-	Label   string
-	label   text.Text
+	// Label may contain a mnemonic, like "&Name", which moves the keyboard focus to Content.
+	Label string
+	label text.Text
+	mn    mnemonic
 }
 
 func NewForm() *Form {
@@ -36,9 +38,10 @@ func (f *Form) AddField(label string, c ui.Component) {
 func (f *Form) PreferredSize(fonts draw.FontLookup) (int, int) {
 	w, h := 0, 5
 	f.w, f.h = 0, 0
-	for _, ff := range f.Fields {
+	for i := range f.Fields {
+		ff := &f.Fields[i]
 		cw, ch := ff.Content.PreferredSize(fonts)
-		lw, lh := ff.label.Size(ff.Label, f.Theme.Font("text"), fonts)
+		lw, lh := ff.label.Size(ff.mn.parse(ff.Label), f.Theme.Font("text"), fonts)
 		if ch < lh {
 			ch = lh
 		}
@@ -60,12 +63,21 @@ func (f *Form) Update(g *draw.Buffer, state *ui.State) {
 	f.measure(g.FontLookup)
 	w, _ := g.Size()
 	y := 5
-	for _, ff := range f.Fields {
+	font := f.Theme.Font("text")
+	for i := range f.Fields {
+		ff := &f.Fields[i]
 		_, ch := ff.Content.PreferredSize(g.FontLookup)
 		if ch < f.h {
 			ch = f.h
 		}
-		ff.label.DrawRight(g, draw.XYWH(5, y, f.w, ch), ff.Label, f.Theme.Font("text"), f.Theme.Color("text"))
+		label := ff.mn.parse(ff.Label)
+		r := draw.XYWH(5, y, f.w, ch)
+		ff.label.DrawRight(g, r, label, font, f.Theme.Color("text"))
+		lw, _ := ff.label.Size(label, font, g.FontLookup)
+		ff.mn.draw(g, state, r.Max.X-lw, r, font, f.Theme.Color("text"))
+		if ff.mn.pressed(state) {
+			state.SetKeyboardFocus(ff.Content)
+		}
 		state.UpdateChild(g, draw.XYXY(f.w+10, y, w-5, y+ch), ff.Content)
 		y += ch + 5
 	}
@@ -73,8 +85,9 @@ func (f *Form) Update(g *draw.Buffer, state *ui.State) {
 
 func (f *Form) measure(fonts draw.FontLookup) {
 	f.w, f.h = 0, 0
-	for _, ff := range f.Fields {
-		w, h := ff.label.Size(ff.Label, f.Theme.Font("text"), fonts)
+	for i := range f.Fields {
+		ff := &f.Fields[i]
+		w, h := ff.label.Size(ff.mn.parse(ff.Label), f.Theme.Font("text"), fonts)
 		if w > f.w {
 			f.w = w
 		}
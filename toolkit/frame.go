@@ -1,6 +1,8 @@
 package toolkit
 
 import (
+	"image"
+
 	"github.com/jfreymuth/ui"
 	"github.com/jfreymuth/ui/draw"
 	"github.com/jfreymuth/ui/text"
@@ -12,9 +14,32 @@ type Frame struct {
 	Color   draw.Color
 	Title   string
 	Icon    string
+	// If a dialog is Movable, it can be moved by dragging the title bar.
+	// If it is Resizable, it can be resized by dragging its edges.
+	// The position and size are kept if the frame is closed and opened again.
+	Movable, Resizable bool
+	// MinWidth and MinHeight limit the size of a resizable dialog.
+	MinWidth, MinHeight int
+	// Cancel is called when escape is pressed while the frame is shown as a dialog.
+	// If Cancel is nil, the dialog is closed.
+	Cancel  func(*ui.State)
 	title   text.Text
+	rect    image.Rectangle // position of the dialog in the window, empty if it has not been placed yet
+	drag    frameDrag
+	pressed bool
+	grab    image.Point
 }
 
+type frameDrag byte
+
+const (
+	frameDragLeft frameDrag = 1 << iota
+	frameDragRight
+	frameDragTop
+	frameDragBottom
+	frameDragMove
+)
+
 func NewFrame(icon, title string, content ui.Component, color draw.Color) *Frame {
 	return &Frame{Theme: DefaultTheme, Content: content, Color: color, Title: title, Icon: icon}
 }
@@ -31,9 +56,139 @@ func (f *Frame) PreferredSize(fonts draw.FontLookup) (int, int) {
 func (f *Frame) Update(g *draw.Buffer, state *ui.State) {
 	w, h := g.Size()
 	_, th := f.title.Size(f.Title, f.Theme.Font("title"), g.FontLookup)
+	if f.Movable || f.Resizable {
+		f.handleDrag(state, w, h, th)
+	}
 	g.Shadow(draw.XYXY(12, 12, w-8, h-8), draw.RGBA(0, 0, 0, .5), 10)
 	g.Fill(draw.XYXY(10, 10, w-10, th+20), f.Color)
 	f.title.DrawCenteredIcon(g, draw.XYXY(15, 10, w-15, th+20), f.Title, f.Theme.Font("title"), f.Theme.Color("title"), f.Icon, 5)
 	g.Fill(draw.XYXY(10, th+20, w-10, h-10), DefaultTheme.Color("background"))
 	state.UpdateChild(g, draw.XYXY(10, th+20, w-10, h-10), f.Content)
 }
+
+// dialogBounds returns the bounds of the frame when it is shown as a dialog.
+// def is the default position, centered in the window.
+func (f *Frame) dialogBounds(def, win image.Rectangle) image.Rectangle {
+	if !f.Movable && !f.Resizable {
+		return def
+	}
+	if f.rect.Empty() {
+		f.rect = def
+	}
+	if !f.Resizable {
+		f.rect.Max = f.rect.Min.Add(def.Size())
+	}
+	// keep the title bar inside the window
+	if f.rect.Max.X > win.Max.X {
+		f.rect = f.rect.Sub(image.Pt(f.rect.Max.X-win.Max.X, 0))
+	}
+	if f.rect.Max.Y > win.Max.Y && f.rect.Dy() <= win.Dy() {
+		f.rect = f.rect.Sub(image.Pt(0, f.rect.Max.Y-win.Max.Y))
+	}
+	if f.rect.Min.X < win.Min.X {
+		f.rect = f.rect.Add(image.Pt(win.Min.X-f.rect.Min.X, 0))
+	}
+	if f.rect.Min.Y < win.Min.Y {
+		f.rect = f.rect.Add(image.Pt(0, win.Min.Y-f.rect.Min.Y))
+	}
+	return f.rect
+}
+
+// handleDrag moves or resizes the dialog.
+// The frame is surrounded by a 10 pixel border, which is used to resize it.
+func (f *Frame) handleDrag(state *ui.State, w, h, th int) {
+	mouse := state.MousePos()
+	if !state.MouseButtonDown(ui.MouseLeft) {
+		f.drag, f.pressed = 0, false
+		if state.IsHovered() {
+			f.setCursor(state, f.dragAt(mouse, w, h, th))
+		}
+		return
+	}
+	if !f.pressed {
+		f.drag, f.pressed = f.dragAt(mouse, w, h, th), true
+		f.grab = mouse
+		return
+	}
+	if f.drag == 0 {
+		return
+	}
+	f.setCursor(state, f.drag)
+	if f.rect.Empty() {
+		return
+	}
+	d := mouse.Sub(f.grab)
+	r := f.rect
+	minW, minH := f.MinWidth+20, f.MinHeight+th+30
+	if minW < 100 {
+		minW = 100
+	}
+	if f.drag == frameDragMove {
+		r = r.Add(d)
+	}
+	if f.drag&frameDragLeft != 0 {
+		r.Min.X += d.X
+		if r.Dx() < minW {
+			r.Min.X = r.Max.X - minW
+		}
+	}
+	if f.drag&frameDragRight != 0 {
+		r.Max.X += d.X
+		if r.Dx() < minW {
+			r.Max.X = r.Min.X + minW
+		}
+	}
+	if f.drag&frameDragTop != 0 {
+		r.Min.Y += d.Y
+		if r.Dy() < minH {
+			r.Min.Y = r.Max.Y - minH
+		}
+	}
+	if f.drag&frameDragBottom != 0 {
+		r.Max.Y += d.Y
+		if r.Dy() < minH {
+			r.Max.Y = r.Min.Y + minH
+		}
+	}
+	if r != f.rect {
+		// the frame's origin moves with r.Min, so the mouse position will be relative to the new origin
+		f.grab = mouse.Sub(r.Min.Sub(f.rect.Min))
+		f.rect = r
+		state.RequestUpdate()
+	}
+}
+
+func (f *Frame) dragAt(p image.Point, w, h, th int) frameDrag {
+	var d frameDrag
+	if f.Resizable {
+		if p.X < 10 {
+			d |= frameDragLeft
+		} else if p.X >= w-10 {
+			d |= frameDragRight
+		}
+		if p.Y < 10 {
+			d |= frameDragTop
+		} else if p.Y >= h-10 {
+			d |= frameDragBottom
+		}
+	}
+	if d == 0 && f.Movable && p.Y < th+20 {
+		d = frameDragMove
+	}
+	return d
+}
+
+func (f *Frame) setCursor(state *ui.State, d frameDrag) {
+	switch d {
+	case frameDragMove:
+		state.SetCursor(ui.CursorMove)
+	case frameDragLeft, frameDragRight:
+		state.SetCursor(ui.CursorResizeHorizontal)
+	case frameDragTop, frameDragBottom:
+		state.SetCursor(ui.CursorResizeVertical)
+	case frameDragLeft | frameDragTop, frameDragRight | frameDragBottom:
+		state.SetCursor(ui.CursorResizeDiagonal)
+	case frameDragRight | frameDragTop, frameDragLeft | frameDragBottom:
+		state.SetCursor(ui.CursorResizeDiagonal2)
+	}
+}
@@ -0,0 +1,239 @@
+package toolkit
+
+import (
+	"fmt"
+	"image"
+	"math"
+
+	"github.com/jfreymuth/ui"
+	"github.com/jfreymuth/ui/draw"
+)
+
+// An ImageView shows an image that can be zoomed with the mouse wheel and moved by dragging it.
+type ImageView struct {
+	Theme *Theme
+	Image *image.RGBA
+	// Checkerboard shows a checkerboard pattern behind the image, to make transparent parts visible.
+	Checkerboard bool
+	// ToolBar contains buttons to change the zoom. It is shown above the image, unless it is nil.
+	ToolBar *ToolBar
+	zoom    float32
+	fit     bool
+	center  [2]float32 // the point of the image in the center of the view
+	changed bool
+	grab    bool
+	grabPos image.Point
+	grabC   [2]float32
+	label   *Label
+	fitB    *ToolButton
+	actualB *ToolButton
+}
+
+// zoomLevels are used by ZoomIn and ZoomOut.
+var zoomLevels = []float32{1. / 16, 1. / 12, 1. / 8, 1. / 6, 1. / 4, 1. / 3, 1. / 2, 2. / 3, 1, 1.5, 2, 3, 4, 6, 8, 12, 16, 24, 32, 48, 64}
+
+func NewImageView(img *image.RGBA) *ImageView {
+	v := &ImageView{Theme: DefaultTheme, Image: img, zoom: 1, fit: true}
+	v.ToolBar = NewToolBar()
+	v.ToolBar.AddButton("zoomOut", "Zoom Out", func(*ui.State) { v.ZoomOut() })
+	v.ToolBar.AddButton("zoomIn", "Zoom In", func(*ui.State) { v.ZoomIn() })
+	v.actualB = v.ToolBar.AddToggleButton("zoomActual", "Actual Size", false, func(*ui.State) { v.SetZoom(1) })
+	v.fitB = v.ToolBar.AddToggleButton("zoomFit", "Fit to Window", true, func(*ui.State) { v.Fit() })
+	v.label = NewLabel("")
+	v.ToolBar.AddComponent("", NewPadding(v.label, 5))
+	return v
+}
+
+func (v *ImageView) SetTheme(theme *Theme) {
+	v.Theme = theme
+	if v.ToolBar != nil {
+		v.ToolBar.SetTheme(theme)
+	}
+}
+
+// SetImage shows a different image, fitted to the view.
+func (v *ImageView) SetImage(img *image.RGBA) {
+	v.Image = img
+	v.Fit()
+}
+
+// ImageChanged has to be called if the pixels of the image have been modified.
+func (v *ImageView) ImageChanged() {
+	v.changed = true
+}
+
+// Zoom returns the current zoom factor, 1 shows the image at its actual size.
+func (v *ImageView) Zoom() float32 {
+	return v.zoom
+}
+
+// SetZoom sets the zoom factor, keeping the center of the view in place.
+func (v *ImageView) SetZoom(zoom float32) {
+	v.fit = false
+	if zoom < zoomLevels[0] {
+		zoom = zoomLevels[0]
+	} else if last := zoomLevels[len(zoomLevels)-1]; zoom > last {
+		zoom = last
+	}
+	v.zoom = zoom
+}
+
+// Fit zooms the image so that it fills the view, and keeps it fitted when the view is resized.
+func (v *ImageView) Fit() {
+	v.fit = true
+}
+
+func (v *ImageView) ZoomIn() {
+	for _, z := range zoomLevels {
+		if z > v.zoom*1.01 {
+			v.SetZoom(z)
+			return
+		}
+	}
+}
+
+func (v *ImageView) ZoomOut() {
+	for i := len(zoomLevels) - 1; i >= 0; i-- {
+		if z := zoomLevels[i]; z < v.zoom*.99 {
+			v.SetZoom(z)
+			return
+		}
+	}
+}
+
+func (v *ImageView) PreferredSize(fonts draw.FontLookup) (int, int) {
+	w, h := 0, 0
+	if v.Image != nil {
+		w, h = v.Image.Rect.Dx(), v.Image.Rect.Dy()
+	}
+	if v.ToolBar != nil {
+		tw, th := v.ToolBar.PreferredSize(fonts)
+		if tw > w {
+			w = tw
+		}
+		h += th
+	}
+	return w, h
+}
+
+func (v *ImageView) Update(g *draw.Buffer, state *ui.State) {
+	w, h := g.Size()
+	view := draw.WH(w, h)
+	if v.ToolBar != nil {
+		v.label.Text = fmt.Sprintf("%d%%", int(v.zoom*100+.5))
+		v.fitB.Checked = v.fit
+		v.actualB.Checked = v.zoom == 1
+		_, th := v.ToolBar.PreferredSize(g.FontLookup)
+		state.UpdateChild(g, draw.WH(w, th), v.ToolBar)
+		view.Min.Y = th
+	}
+	g.Fill(view, v.Theme.Color("altBackground"))
+	if v.Image == nil || view.Empty() || v.Image.Rect.Empty() {
+		return
+	}
+	iw, ih := float32(v.Image.Rect.Dx()), float32(v.Image.Rect.Dy())
+	vw, vh := float32(view.Dx()), float32(view.Dy())
+	if v.fit {
+		v.zoom = vw / iw
+		if z := vh / ih; z < v.zoom {
+			v.zoom = z
+		}
+	}
+
+	mouse := state.MousePos().Sub(view.Min)
+	// the mouse position relative to the center of the view
+	mx, my := float32(mouse.X)-vw/2, float32(mouse.Y)-vh/2
+	if scroll := state.Scroll(); scroll.Y != 0 && mouse.In(draw.WH(view.Dx(), view.Dy())) {
+		// zoom around the cursor: the point of the image under the cursor stays in place
+		px, py := v.center[0]+mx/v.zoom, v.center[1]+my/v.zoom
+		if scroll.Y > 0 {
+			v.ZoomIn()
+		} else {
+			v.ZoomOut()
+		}
+		v.center = [2]float32{px - mx/v.zoom, py - my/v.zoom}
+		state.ConsumeScroll()
+		state.RequestUpdate()
+	}
+	if !state.MouseButtonDown(ui.MouseLeft) {
+		v.grab = false
+	} else if v.grab {
+		d := mouse.Sub(v.grabPos)
+		v.center = [2]float32{v.grabC[0] - float32(d.X)/v.zoom, v.grabC[1] - float32(d.Y)/v.zoom}
+	} else if state.MousePress(ui.MouseLeft) && state.IsHovered() && mouse.In(draw.WH(view.Dx(), view.Dy())) {
+		// a drag only starts if the button is pressed on the image, not if it is moved there while pressed
+		v.grab = true
+		v.grabPos, v.grabC = mouse, v.center
+	}
+	if iw*v.zoom > vw || ih*v.zoom > vh {
+		if v.grab || state.IsHovered() && mouse.Y >= 0 {
+			state.SetCursor(ui.CursorMove)
+		}
+	}
+	v.center[0] = clampCenter(v.center[0], iw, vw/v.zoom)
+	v.center[1] = clampCenter(v.center[1], ih, vh/v.zoom)
+
+	g.Push(view)
+	defer g.Pop()
+	// the position of the image's top left corner in the view
+	ox, oy := vw/2-v.center[0]*v.zoom, vh/2-v.center[1]*v.zoom
+	// only the part of the image that is visible is drawn
+	sub := image.Rect(
+		int(math.Floor(float64(-ox/v.zoom))), int(math.Floor(float64(-oy/v.zoom))),
+		int(math.Ceil(float64((vw-ox)/v.zoom))), int(math.Ceil(float64((vh-oy)/v.zoom))),
+	).Intersect(image.Rect(0, 0, int(iw), int(ih)))
+	r := image.Rect(
+		round(ox+float32(sub.Min.X)*v.zoom), round(oy+float32(sub.Min.Y)*v.zoom),
+		round(ox+float32(sub.Max.X)*v.zoom), round(oy+float32(sub.Max.Y)*v.zoom),
+	)
+	if v.Checkerboard {
+		drawCheckerboard(g, r.Intersect(draw.WH(view.Dx(), view.Dy())))
+	}
+	g.SubImage(r, v.Image, sub, draw.White, v.changed)
+	v.changed = false
+}
+
+// clampCenter keeps as much of the image visible as possible. Images smaller than the view are centered.
+func clampCenter(c, size, view float32) float32 {
+	if size <= view {
+		return size / 2
+	}
+	if c < view/2 {
+		return view / 2
+	}
+	if c > size-view/2 {
+		return size - view/2
+	}
+	return c
+}
+
+func round(f float32) int {
+	return int(math.Floor(float64(f) + .5))
+}
+
+var checkerboard *image.RGBA
+
+func drawCheckerboard(g *draw.Buffer, r image.Rectangle) {
+	const size, square = 64, 8
+	if checkerboard == nil {
+		checkerboard = image.NewRGBA(image.Rect(0, 0, size, size))
+		for i := 0; i < len(checkerboard.Pix); i += 4 {
+			x, y := i/4%size, i/4/size
+			c := uint8(0xcc)
+			if (x/square+y/square)%2 == 1 {
+				c = 0x99
+			}
+			checkerboard.Pix[i], checkerboard.Pix[i+1], checkerboard.Pix[i+2], checkerboard.Pix[i+3] = c, c, c, 0xff
+		}
+	}
+	if r.Empty() {
+		return
+	}
+	g.Push(r)
+	for y := 0; y < r.Dy(); y += size {
+		for x := 0; x < r.Dx(); x += size {
+			g.Image(draw.XYWH(x, y, size, size), checkerboard, draw.White, false)
+		}
+	}
+	g.Pop()
+}
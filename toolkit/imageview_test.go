@@ -0,0 +1,44 @@
+package toolkit
+
+import (
+	"image"
+	"testing"
+
+	"github.com/jfreymuth/ui"
+	"github.com/jfreymuth/ui/draw"
+)
+
+func TestImageViewDrag(t *testing.T) {
+	frame := func(st *ui.BackendState, v *ImageView, x, y int, b ui.MouseButton) {
+		st.ResetEvents()
+		st.SetMousePosition(x, y)
+		st.SetMouseButtons(b)
+		g := &draw.Buffer{}
+		g.Reset(200, 100)
+		st.UpdateChild(g, draw.WH(100, 100), v)
+	}
+	for _, outside := range []bool{false, true} {
+		var st ui.BackendState
+		st.SetHovered(true)
+		v := NewImageView(image.NewRGBA(image.Rect(0, 0, 400, 400)))
+		v.ToolBar = nil
+		v.SetZoom(1)
+		if outside {
+			// the button is pressed outside of the view and moved onto it
+			frame(&st, v, 150, 50, ui.MouseLeft)
+		} else {
+			frame(&st, v, 50, 50, 0)
+		}
+		frame(&st, v, 50, 50, ui.MouseLeft)
+		c := v.center
+		frame(&st, v, 30, 40, ui.MouseLeft)
+		frame(&st, v, 30, 40, 0)
+		want := [2]float32{c[0] + 20, c[1] + 10}
+		if outside {
+			want = c
+		}
+		if v.center != want {
+			t.Errorf("outside: %v, center %v, want %v", outside, v.center, want)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package toolkit
 
 import (
+	"image"
 	"strings"
 	"time"
 
@@ -15,23 +16,89 @@ type List struct {
 	Selected int
 	Changed  func(*ui.State, ListItem)
 	Action   func(*ui.State, ListItem)
+	// If MultiSelect is set, several items can be selected using Ctrl and Shift.
+	// Selected is then the item that was clicked last, ListItem.Selected marks all selected items.
+	MultiSelect bool
+	// Columns are the widths of additional columns, that show ListItem.Details right of the text.
+	// If the list has columns, long texts are cut off instead of making the list wider.
+	Columns []int
+
+	anchor int // start of the range selected with Shift
 
 	grab    bool
 	search  string
 	searchT time.Time
+	menu    *Menu
+	noMenu  bool // lists in popups have no context menu
 }
 
 type ListItem struct {
 	Icon string
 	Text string
-	text text.Text
+	// Selected is only used if the List allows multiple selections.
+	Selected bool
+	// Details are shown in the List's columns.
+	Details []string
+	text    text.Text
+	details []text.Text
+	match   [2]int // highlighted part of Text
 }
 
 func NewList() *List {
 	return &List{Theme: DefaultTheme}
 }
 
-func (l *List) SetTheme(theme *Theme) { l.Theme = theme }
+func (l *List) SetTheme(theme *Theme) {
+	l.Theme = theme
+	if l.menu != nil {
+		l.menu.SetTheme(theme)
+	}
+}
+
+// ContextMenu returns the menu that is shown when an item is right-clicked.
+// Applications may add their own items, the selected item is the one that was clicked.
+func (l *List) ContextMenu() *Menu {
+	if l.menu == nil {
+		l.menu = NewPopupMenu("")
+		l.menu.SetTheme(l.Theme)
+		l.menu.AddItem("&Copy", l.Copy)
+	}
+	return l.menu
+}
+
+// Copy copies the text of the selected item to the clipboard.
+func (l *List) Copy(state *ui.State) {
+	if l.Selected >= 0 && l.Selected < len(l.Items) {
+		state.SetClipboardString(l.Items[l.Selected].Text)
+	}
+}
+
+// SelectedItems returns the selected items.
+func (l *List) SelectedItems() []ListItem {
+	var items []ListItem
+	for i, item := range l.Items {
+		if l.isSelected(i) {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// SelectAll selects all items, if the List allows multiple selections.
+func (l *List) SelectAll() {
+	if l.MultiSelect {
+		for i := range l.Items {
+			l.Items[i].Selected = true
+		}
+	}
+}
+
+func (l *List) isSelected(i int) bool {
+	if l.MultiSelect {
+		return l.Items[i].Selected
+	}
+	return i == l.Selected
+}
 
 func (l *List) AddItem(text string) { l.AddItemIcon("", text) }
 func (l *List) AddItemIcon(icon, text string) {
@@ -88,6 +155,12 @@ func (l *List) PreferredSize(fonts draw.FontLookup) (int, int) {
 			h = ih
 		}
 	}
+	if len(l.Columns) > 0 {
+		w = 100
+		for _, c := range l.Columns {
+			w += c
+		}
+	}
 	return w, h * len(l.Items)
 }
 
@@ -111,28 +184,55 @@ func (l *List) Update(g *draw.Buffer, state *ui.State) {
 						state.RequestUpdate()
 					}
 				} else {
+					l.selectTo(state, sel, true)
 					l.change(state, sel, h)
 					state.ClosePopups()
 				}
 			}
 		}
+	} else if state.MouseButtonDown(ui.MouseRight) && !l.noMenu {
+		if sel := mouse.Y / h; sel >= 0 && sel < len(l.Items) {
+			if !l.isSelected(sel) {
+				l.selectTo(state, sel, false)
+			}
+			if sel != l.Selected {
+				l.change(state, sel, h)
+			}
+			l.ContextMenu().OpenPopupMenu(mouse, state, g.FontLookup)
+			state.InitiateDrag(ui.MenuDrag)
+		}
 	} else {
 		l.grab = false
 		for _, k := range state.KeyPresses() {
 			switch k {
 			case ui.KeyUp:
 				if l.Selected > 0 {
+					l.selectTo(state, l.Selected-1, false)
 					l.change(state, l.Selected-1, h)
 				}
 			case ui.KeyDown:
 				if l.Selected < len(l.Items)-1 {
+					l.selectTo(state, l.Selected+1, false)
 					l.change(state, l.Selected+1, h)
 				}
+			case ui.KeyA:
+				if l.MultiSelect && state.HasModifiers(ui.Control) {
+					l.SelectAll()
+					l.change(state, l.Selected, h)
+				} else {
+					state.IgnoreKeyPress(k)
+				}
 			case ui.KeySpace, ui.KeyEnter:
 				if l.Selected >= 0 && l.Selected < len(l.Items) && l.Action != nil {
 					l.Action(state, l.Items[l.Selected])
 					state.RequestUpdate()
 				}
+			case ui.KeyMenu:
+				if !l.noMenu && l.Selected >= 0 && l.Selected < len(l.Items) {
+					l.ContextMenu().OpenPopupMenu(image.Pt(0, (l.Selected+1)*h), state, g.FontLookup)
+				}
+			default:
+				state.IgnoreKeyPress(k)
 			}
 		}
 		if text := state.TextInput(); text != "" {
@@ -145,6 +245,7 @@ func (l *List) Update(g *draw.Buffer, state *ui.State) {
 			for i, item := range l.Items {
 				ls := len(l.search)
 				if len(item.Text) >= ls && strings.EqualFold(l.search, item.Text[:ls]) {
+					l.selectTo(state, i, false)
 					l.change(state, i, h)
 					break
 				}
@@ -157,7 +258,7 @@ func (l *List) Update(g *draw.Buffer, state *ui.State) {
 		item := &l.Items[i]
 		x, y := 2, i*h
 		r := draw.XYWH(x, y, w, h)
-		if i == l.Selected {
+		if l.isSelected(i) {
 			if state.HasKeyboardFocus() {
 				g.Fill(r, l.Theme.Color("selection"))
 			} else {
@@ -166,7 +267,83 @@ func (l *List) Update(g *draw.Buffer, state *ui.State) {
 		} else if hov && mouse.In(r) {
 			g.Fill(r, l.Theme.Color("buttonHovered"))
 		}
-		item.text.DrawLeftIcon(g, draw.XYXY(x, y, w-2, y+h), item.Text, l.Theme.Font("text"), l.Theme.Color("text"), item.Icon, 3)
+		if l.MultiSelect && i == l.Selected && state.HasKeyboardFocus() {
+			g.Outline(r, l.Theme.Color("text"))
+		}
+		if a, b := item.match[0], item.match[1]; a < b && b <= len(item.Text) {
+			m := g.FontLookup.Metrics(l.Theme.Font("text"))
+			tx := x
+			if item.Icon != "" {
+				tx += h + 3
+			}
+			g.Fill(draw.XYXY(tx+int(m.Advance(item.Text[:a])), y, tx+int(m.Advance(item.Text[:b])), y+h), l.Theme.Color("highlight"))
+		}
+		if len(l.Columns) == 0 {
+			item.text.DrawLeftIcon(g, draw.XYXY(x, y, w-2, y+h), item.Text, l.Theme.Font("text"), l.Theme.Color("text"), item.Icon, 3)
+		}
+	}
+	if len(l.Columns) > 0 {
+		l.drawColumns(g, w, h)
+	}
+}
+
+// drawColumns draws the texts of a list with columns, each column is clipped so that long texts don't overlap.
+func (l *List) drawColumns(g *draw.Buffer, w, h int) {
+	font, color := l.Theme.Font("text"), l.Theme.Color("text")
+	x := w - 2
+	for _, c := range l.Columns {
+		x -= c
+	}
+	g.Push(draw.XYXY(0, 0, x, len(l.Items)*h))
+	for i := range l.Items {
+		item := &l.Items[i]
+		item.text.DrawLeftIcon(g, draw.XYWH(2, i*h, x-2, h), item.Text, font, color, item.Icon, 3)
+	}
+	g.Pop()
+	for j, c := range l.Columns {
+		g.Push(draw.XYWH(x, 0, c, len(l.Items)*h))
+		for i := range l.Items {
+			item := &l.Items[i]
+			if j < len(item.Details) {
+				if len(item.details) < len(item.Details) {
+					item.details = make([]text.Text, len(item.Details))
+				}
+				item.details[j].DrawLeft(g, draw.XYWH(5, i*h, c-5, h), item.Details[j], font, color)
+			}
+		}
+		g.Pop()
+		x += c
+	}
+}
+
+// selectTo updates the selected items after Selected has been changed to i.
+// Shift selects the range from the last item that was selected without Shift, Ctrl toggles items.
+func (l *List) selectTo(state *ui.State, i int, toggle bool) {
+	if !l.MultiSelect || i < 0 || i >= len(l.Items) {
+		return
+	}
+	if state.HasModifiers(ui.Shift) {
+		a, b := l.anchor, i
+		if a > b {
+			a, b = b, a
+		}
+		if !state.HasModifiers(ui.Control) {
+			for j := range l.Items {
+				l.Items[j].Selected = false
+			}
+		}
+		for j := a; j <= b && j < len(l.Items); j++ {
+			l.Items[j].Selected = true
+		}
+		return
+	}
+	l.anchor = i
+	if toggle && state.HasModifiers(ui.Control) {
+		l.Items[i].Selected = !l.Items[i].Selected
+		return
+	}
+	for j := range l.Items {
+		l.Items[j].Selected = j == i
 	}
 }
 
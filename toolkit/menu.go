@@ -9,23 +9,84 @@ import (
 )
 
 type MenuItem struct {
-	Theme  *Theme
-	Text   string
-	Icon   string
-	text   text.Text
-	Action func(*ui.State)
-	parent menuParent
+	Theme *Theme
+	// Text may contain a mnemonic like "&Save", a literal "&" must be written as "&&".
+	Text string
+	Icon string
+	// Accelerator is shown right-aligned next to the text, e.g. "Ctrl+S".
+	Accelerator string
+	// Disabled items are grayed out, can not be activated, and are skipped by keyboard navigation.
+	Disabled bool
+	// Checked is the state of check items and radio items.
+	Checked bool
+	text    text.Text
+	acc     text.Text
+	mn      mnemonic
+	kind    byte
+	Action  func(*ui.State)
+	parent  menuParent
 }
 
+const (
+	menuItemNormal = iota
+	menuItemCheck
+	menuItemRadio
+)
+
 func (m *MenuItem) SetTheme(theme *Theme) { m.Theme = theme }
 
 func (m *MenuItem) PreferredSize(fonts draw.FontLookup) (int, int) {
-	w, h := m.text.SizeIcon(m.Text, m.Theme.Font("text"), m.Icon, 4, fonts)
+	w, h := m.text.SizeIcon(m.mn.parse(m.Text), m.Theme.Font("text"), m.icon(), 4, fonts)
+	if m.Accelerator != "" {
+		aw, _ := m.acc.Size(m.Accelerator, m.Theme.Font("text"), fonts)
+		w += aw + 20
+	}
 	return w + 10, h + 6
 }
 
+func (m *MenuItem) icon() string {
+	switch m.kind {
+	case menuItemCheck:
+		if m.Checked {
+			return "checkboxChecked"
+		}
+		return "checkbox"
+	case menuItemRadio:
+		if m.Checked {
+			return "radiobuttonSelected"
+		}
+		return "radiobutton"
+	}
+	return m.Icon
+}
+
+func (m *MenuItem) activate(state *ui.State) {
+	state.ClosePopups()
+	switch m.kind {
+	case menuItemCheck:
+		m.Checked = !m.Checked
+	case menuItemRadio:
+		if menu, ok := m.parent.(*Menu); ok {
+			menu.selectRadio(m)
+		}
+	}
+	if m.Action != nil {
+		m.Action(state)
+	}
+}
+
 func (m *MenuItem) Update(g *draw.Buffer, state *ui.State) {
 	w, h := g.Size()
+	text := m.mn.parse(m.Text)
+	color := m.Theme.Color("text")
+	if m.Disabled {
+		color = m.Theme.Color("textDisabled")
+		m.text.DrawLeftIcon(g, draw.XYXY(5, 0, w-5, h), text, m.Theme.Font("text"), color, m.icon(), 4)
+		if m.Accelerator != "" {
+			m.acc.DrawRight(g, draw.XYXY(5, 0, w-5, h), m.Accelerator, m.Theme.Font("text"), color)
+		}
+		return
+	}
 	if state.IsHovered() {
 		state.SetKeyboardFocus(m)
 		drag, drop := state.DraggedContent()
@@ -34,10 +95,7 @@ func (m *MenuItem) Update(g *draw.Buffer, state *ui.State) {
 			drag = ui.MenuDrag
 		}
 		if drop && drag == ui.MenuDrag {
-			state.ClosePopups()
-			if m.Action != nil {
-				m.Action(state)
-			}
+			m.activate(state)
 		}
 	}
 	if state.HasKeyboardFocus() {
@@ -52,24 +110,52 @@ func (m *MenuItem) Update(g *draw.Buffer, state *ui.State) {
 			case ui.KeyLeft:
 				state.SetKeyboardFocus(m.parent)
 			case ui.KeySpace, ui.KeyEnter:
-				state.ClosePopups()
-				if m.Action != nil {
-					m.Action(state)
-				}
+				m.activate(state)
 			}
 		}
 	}
-	m.text.DrawLeftIcon(g, draw.XYXY(5, 0, w-5, h), m.Text, m.Theme.Font("text"), m.Theme.Color("text"), m.Icon, 4)
+	if m.mn.pressed(state) {
+		m.activate(state)
+	}
+	m.text.DrawLeftIcon(g, draw.XYXY(5, 0, w-5, h), text, m.Theme.Font("text"), color, m.icon(), 4)
+	x := 5
+	if icon := m.icon(); icon != "" {
+		_, th := m.text.Size(text, m.Theme.Font("text"), g.FontLookup)
+		x += th + 4
+	}
+	m.mn.draw(g, state, x, draw.WH(w, h), m.Theme.Font("text"), color)
+	if m.Accelerator != "" {
+		m.acc.DrawRight(g, draw.XYXY(5, 0, w-5, h), m.Accelerator, m.Theme.Font("text"), color)
+	}
+}
+
+type menuSeparator struct {
+	theme *Theme
+}
+
+func (s *menuSeparator) SetTheme(theme *Theme) { s.theme = theme }
+
+func (s *menuSeparator) PreferredSize(fonts draw.FontLookup) (int, int) { return 0, 7 }
+
+func (s *menuSeparator) Update(g *draw.Buffer, state *ui.State) {
+	w, h := g.Size()
+	g.Fill(draw.XYWH(5, h/2, w-10, 1), s.theme.Color("separator"))
 }
 
 type Menu struct {
-	Theme  *Theme
-	Text   string
-	text   text.Text
-	parent menuParent
-	items  []ui.Component
-	open   *Menu
-	popup  ui.Popup
+	Theme *Theme
+	// Text may contain a mnemonic like "&File", a literal "&" must be written as "&&".
+	Text string
+	// AboutToShow is called before the menu is opened.
+	// It can be used to update the menu's items.
+	AboutToShow func(*ui.State, *Menu)
+	text        text.Text
+	mn          mnemonic
+	parent      menuParent
+	items       []ui.Component
+	open        *Menu
+	popup       ui.Popup
+	opening     bool
 }
 
 func NewPopupMenu(text string) *Menu {
@@ -95,6 +181,66 @@ func (m *Menu) AddItemIcon(icon, text string, action func(*ui.State)) *MenuItem
 	return i
 }
 
+// AddCheckItem adds an item that toggles its Checked state when it is activated.
+func (m *Menu) AddCheckItem(text string, checked bool, action func(*ui.State)) *MenuItem {
+	i := &MenuItem{parent: m, Action: action, Theme: m.Theme, Text: text, Checked: checked, kind: menuItemCheck}
+	m.items = append(m.items, i)
+	return i
+}
+
+// AddRadioItem adds an item that is part of a group of mutually exclusive items.
+// Consecutive radio items belong to the same group.
+func (m *Menu) AddRadioItem(text string, checked bool, action func(*ui.State)) *MenuItem {
+	i := &MenuItem{parent: m, Action: action, Theme: m.Theme, Text: text, Checked: checked, kind: menuItemRadio}
+	m.items = append(m.items, i)
+	return i
+}
+
+func (m *Menu) AddSeparator() {
+	m.items = append(m.items, &menuSeparator{m.Theme})
+}
+
+// Clear removes all items from the menu.
+func (m *Menu) Clear() {
+	m.items = nil
+}
+
+func (m *Menu) selectRadio(item *MenuItem) {
+	for i, c := range m.items {
+		if c != item {
+			continue
+		}
+		for j := i - 1; j >= 0 && isRadio(m.items[j]); j-- {
+			m.items[j].(*MenuItem).Checked = false
+		}
+		for j := i + 1; j < len(m.items) && isRadio(m.items[j]); j++ {
+			m.items[j].(*MenuItem).Checked = false
+		}
+	}
+	item.Checked = true
+}
+
+func isRadio(c ui.Component) bool {
+	item, ok := c.(*MenuItem)
+	return ok && item.kind == menuItemRadio
+}
+
+// firstItem returns the first item that can receive the keyboard focus.
+func (m *Menu) firstItem() ui.Component {
+	for _, c := range m.items {
+		switch c := c.(type) {
+		case *MenuItem:
+			if !c.Disabled {
+				return c
+			}
+		case *menuSeparator:
+		default:
+			return c
+		}
+	}
+	return m
+}
+
 func (m *Menu) AddMenu(text string) *Menu {
 	menu := &Menu{parent: m, Theme: m.Theme, Text: text}
 	m.items = append(m.items, menu)
@@ -102,6 +248,9 @@ func (m *Menu) AddMenu(text string) *Menu {
 }
 
 func (m *Menu) OpenPopupMenu(p image.Point, state *ui.State, fonts draw.FontLookup) {
+	if m.AboutToShow != nil {
+		m.AboutToShow(state, m)
+	}
 	c := &menuBackground{&Stack{m.items}, m.Theme}
 	w, h := c.PreferredSize(fonts)
 	win := state.WindowBounds()
@@ -129,7 +278,7 @@ func (m *Menu) setOpen(p *Menu) {
 func (m *Menu) isMenuBar() bool { return false }
 
 func (m *Menu) PreferredSize(fonts draw.FontLookup) (int, int) {
-	w, h := m.text.Size(m.Text, m.Theme.Font("text"), fonts)
+	w, h := m.text.Size(m.mn.parse(m.Text), m.Theme.Font("text"), fonts)
 	if m.parent.isMenuBar() {
 		return w + 10, h + 6
 	} else {
@@ -148,13 +297,26 @@ func (m *Menu) Update(g *draw.Buffer, state *ui.State) {
 			case ui.KeyUp:
 				state.FocusPrevious()
 			case ui.KeyRight:
-				state.SetKeyboardFocus(m.items[0])
+				state.SetKeyboardFocus(m.firstItem())
 			case ui.KeyLeft:
 				state.SetKeyboardFocus(m.parent)
 			}
 		}
+	} else if isOpen(m.popup) && state.HasKeyboardFocus() {
+		for _, k := range state.KeyPresses() {
+			switch k {
+			case ui.KeyDown, ui.KeySpace, ui.KeyEnter:
+				state.SetKeyboardFocus(m.firstItem())
+			case ui.KeyLeft:
+				m.parent.(*MenuBar).openNext(state, m, -1)
+			case ui.KeyRight:
+				m.parent.(*MenuBar).openNext(state, m, 1)
+			}
+		}
 	}
-	open := state.HasPopups() && state.IsHovered()
+	keyboard := m.opening || m.mn.pressed(state)
+	m.opening = false
+	open := state.HasPopups() && state.IsHovered() || keyboard
 	if state.IsHovered() {
 		g.Fill(draw.WH(w, h), m.Theme.Color("buttonHovered"))
 		if state.MouseButtonDown(ui.MouseLeft) {
@@ -167,11 +329,14 @@ func (m *Menu) Update(g *draw.Buffer, state *ui.State) {
 	}
 	if open {
 		if !isOpen(m.popup) {
+			if m.AboutToShow != nil {
+				m.AboutToShow(state, m)
+			}
 			popup := &menuBackground{&Stack{m.items}, m.Theme}
 			mw, mh := popup.PreferredSize(g.FontLookup)
 			m.parent.setOpen(m)
 			if submenu {
-				m.popup = state.OpenPopup(draw.XYWH(w-3, -1, mw, mh), popup)
+				m.popup = state.OpenPopup(draw.XYWH(w-3, -1-popup.padding(), mw, mh), popup)
 			} else {
 				m.popup = state.OpenPopup(draw.XYWH(-3, h-1, mw, mh), popup)
 			}
@@ -181,13 +346,18 @@ func (m *Menu) Update(g *draw.Buffer, state *ui.State) {
 			m.setOpen(nil)
 			state.SetKeyboardFocus(m)
 		}
+		if keyboard {
+			state.SetKeyboardFocus(m.firstItem())
+		}
 	}
 	if submenu && isOpen(m.popup) {
 		g.Fill(draw.WH(w, h), m.Theme.Color("selection"))
 	}
-	m.text.DrawLeft(g, draw.XYXY(5, 0, w-5, h), m.Text, m.Theme.Font("text"), m.Theme.Color("text"))
+	text := m.mn.parse(m.Text)
+	m.text.DrawLeft(g, draw.XYXY(5, 0, w-5, h), text, m.Theme.Font("text"), m.Theme.Color("text"))
+	m.mn.draw(g, state, 5, draw.WH(w, h), m.Theme.Font("text"), m.Theme.Color("text"))
 	if submenu {
-		_, th := m.text.Size(m.Text, m.Theme.Font("text"), g.FontLookup)
+		_, th := m.text.Size(text, m.Theme.Font("text"), g.FontLookup)
 		g.Icon(draw.XYXY(w-th-5, 0, w-5, h), "right", m.Theme.Color("text"))
 	}
 }
@@ -228,6 +398,17 @@ func (m *MenuBar) setOpen(p *Menu) {
 
 func (m *MenuBar) isMenuBar() bool { return true }
 
+// openNext opens the menu next to the given one.
+func (m *MenuBar) openNext(state *ui.State, menu *Menu, d int) {
+	for i, o := range m.menus {
+		if o == menu {
+			m.menus[(i+d+len(m.menus))%len(m.menus)].opening = true
+			state.RequestUpdate()
+			return
+		}
+	}
+}
+
 func (m *MenuBar) PreferredSize(fonts draw.FontLookup) (int, int) {
 	w := 0
 	h := 0
@@ -245,6 +426,13 @@ func (m *MenuBar) PreferredSize(fonts draw.FontLookup) (int, int) {
 func (m *MenuBar) Update(g *draw.Buffer, state *ui.State) {
 	w, h := g.Size()
 	g.Fill(draw.WH(w, h), m.Theme.Color("altBackground"))
+	if state.HotKey(ui.Shortcut{Key: ui.KeyF10}) {
+		if isOpen(m.popup) {
+			state.ClosePopups()
+		} else if len(m.menus) > 0 {
+			m.menus[0].opening = true
+		}
+	}
 	x := 0
 	for _, menu := range m.menus {
 		mw, _ := menu.PreferredSize(g.FontLookup)
@@ -271,15 +459,25 @@ type menuBackground struct {
 }
 
 func (m *menuBackground) PreferredSize(fonts draw.FontLookup) (int, int) {
-	w, h := m.Content.PreferredSize(fonts)
-	return w + 6, h + 6
+	return m.size(m.Content.PreferredSize(fonts))
+}
+
+// size returns the size of the popup for content of the given size.
+func (m *menuBackground) size(w, h int) (int, int) {
+	return w + 6, h + 6 + m.padding()*2
+}
+
+// padding keeps the content out of the rounded corners.
+func (m *menuBackground) padding() int {
+	return m.theme.Size("cornerRadius") / 2
 }
 
 func (m *menuBackground) Update(g *draw.Buffer, state *ui.State) {
 	w, h := g.Size()
+	p := m.padding()
 	g.Shadow(draw.XYXY(3, 3, w-3, h-3), m.theme.Color("shadow"), 4)
-	g.Fill(draw.XYXY(3, 1, w-3, h-5), m.theme.Color("background"))
-	state.UpdateChild(g, draw.XYXY(3, 1, w-3, h-5), m.Content)
+	g.RoundedFill(draw.XYXY(3, 1, w-3, h-5), m.theme.Size("cornerRadius"), m.theme.Color("background"))
+	state.UpdateChild(g, draw.XYXY(3, 1+p, w-3, h-5-p), m.Content)
 }
 
 func isOpen(p ui.Popup) bool {
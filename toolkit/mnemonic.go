@@ -0,0 +1,83 @@
+package toolkit
+
+import (
+	"image"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/jfreymuth/ui"
+	"github.com/jfreymuth/ui/draw"
+)
+
+// A mnemonic is a letter marked with "&" in a text, like "&File".
+// The letter is underlined while Alt is held, and pressing Alt and the letter activates the component.
+// Every "&" followed by a letter or digit is a marker, so a literal "&" must be written as "&&", e.g. "R&&D".
+// An "&" that is not followed by a letter or digit is displayed as is.
+// If several visible components use the same mnemonic, pressing it repeatedly activates them in turn.
+type mnemonic struct {
+	src  string
+	text string // text without markers
+	pos  int    // position of the marked character in text, or -1
+	key  ui.Key
+}
+
+// parse returns the text to display.
+func (m *mnemonic) parse(s string) string {
+	if s == m.src && s != "" {
+		return m.text
+	}
+	m.src, m.pos, m.key = s, -1, ui.KeyUnknown
+	if !strings.Contains(s, "&") {
+		m.text = s
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '&' && i+1 < len(s) {
+			if s[i+1] == '&' {
+				i++
+			} else if r, _ := utf8.DecodeRuneInString(s[i+1:]); mnemonicKey(r) != ui.KeyUnknown {
+				if m.pos < 0 {
+					m.pos, m.key = b.Len(), mnemonicKey(r)
+				}
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	m.text = b.String()
+	return m.text
+}
+
+func mnemonicKey(r rune) ui.Key {
+	r = unicode.ToLower(r)
+	switch {
+	case r >= 'a' && r <= 'z':
+		return ui.KeyA + ui.Key(r-'a')
+	case r >= '1' && r <= '9':
+		return ui.Key1 + ui.Key(r-'1')
+	case r == '0':
+		return ui.Key0
+	}
+	return ui.KeyUnknown
+}
+
+// pressed registers the mnemonic and returns true if it was pressed.
+func (m *mnemonic) pressed(state *ui.State) bool {
+	return m.key != ui.KeyUnknown && state.Mnemonic(m.key)
+}
+
+// draw underlines the marked character while Alt is held.
+// x is the position of the text's first character, r is the rectangle the text was vertically centered in.
+func (m *mnemonic) draw(g *draw.Buffer, state *ui.State, x int, r image.Rectangle, font draw.Font, color draw.Color) {
+	if m.pos < 0 || !state.HasModifiers(ui.Alt) {
+		return
+	}
+	metrics := g.FontLookup.Metrics(font)
+	_, size := utf8.DecodeRuneInString(m.text[m.pos:])
+	x1 := x + int(metrics.Advance(m.text[:m.pos]))
+	x2 := x + int(metrics.Advance(m.text[:m.pos+size]))
+	y := (r.Min.Y+r.Max.Y)/2 + (metrics.Ascent()-metrics.Descent())/2 + 1
+	g.Fill(draw.XYXY(x1, y, x2, y+1), color)
+}
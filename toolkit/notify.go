@@ -0,0 +1,167 @@
+package toolkit
+
+import (
+	"image"
+	"strings"
+	"time"
+
+	"github.com/jfreymuth/ui"
+	"github.com/jfreymuth/ui/draw"
+	"github.com/jfreymuth/ui/text"
+)
+
+// A Notification is a message that is shown in a corner of the window without interrupting the user.
+type Notification struct {
+	Icon    string
+	Title   string
+	Message string
+	theme   *Theme
+	timeout float32
+	anim    float32
+	closing bool
+	bounds  image.Rectangle
+	title   text.Text
+	lines   []text.Text
+	close   toastButton
+	actions []*toastButton
+}
+
+// Notify shows a notification in the bottom right corner of the Root.
+// The notification disappears after the timeout, or when it is dismissed. If timeout is 0, it has to be dismissed.
+// Unlike dialogs, notifications do not receive keyboard focus and do not block input to other components.
+func Notify(state *ui.State, icon, title, message string, timeout time.Duration) *Notification {
+	n := &Notification{Icon: icon, Title: title, Message: message, theme: DefaultTheme, timeout: float32(timeout.Seconds())}
+	n.close.n = n
+	n.close.icon = "close"
+	if r, ok := state.Root().(*Root); ok {
+		n.theme = r.Theme
+		r.notifications = append(r.notifications, n)
+	}
+	state.RequestUpdate()
+	return n
+}
+
+// AddAction adds a button to the notification. Clicking the button calls the action and dismisses the notification.
+func (n *Notification) AddAction(text string, action func(*ui.State)) {
+	n.actions = append(n.actions, &toastButton{n: n, text: text, action: action})
+}
+
+// Dismiss hides the notification.
+func (n *Notification) Dismiss(state *ui.State) {
+	n.closing = true
+	state.RequestUpdate()
+}
+
+const notificationWidth = 300
+
+func (n *Notification) PreferredSize(fonts draw.FontLookup) (int, int) {
+	lh := fonts.Metrics(n.theme.Font("text")).LineHeight()
+	th := fonts.Metrics(n.theme.Font("title")).LineHeight()
+	h := 10 + th + 5 + strings.Count(n.Message, "\n")*lh + lh + 10
+	if len(n.actions) > 0 {
+		_, bh := n.actions[0].PreferredSize(fonts)
+		h += bh + 5
+	}
+	return notificationWidth, h
+}
+
+func (n *Notification) Update(g *draw.Buffer, state *ui.State) {
+	w, h := g.Size()
+	g.Shadow(draw.XYXY(3, 3, w, h), n.theme.Color("shadow"), 6)
+	g.Fill(draw.XYXY(0, 0, w-3, h-3), n.theme.Color("background"))
+	g.Outline(draw.XYXY(0, 0, w-3, h-3), n.theme.Color("separator"))
+	w, h = w-3, h-3
+	tfont, font := n.theme.Font("title"), n.theme.Font("text")
+	th := g.FontLookup.Metrics(tfont).LineHeight()
+	lh := g.FontLookup.Metrics(font).LineHeight()
+	n.title.DrawLeftIcon(g, draw.XYXY(10, 10, w-15-th, 10+th), n.Title, tfont, n.theme.Color("title"), n.Icon, 5)
+	state.UpdateChild(g, draw.XYWH(w-10-th, 10, th, th), &n.close)
+	lines := strings.Split(n.Message, "\n")
+	if len(n.lines) < len(lines) {
+		n.lines = make([]text.Text, len(lines))
+	}
+	y := 15 + th
+	for i, l := range lines {
+		n.lines[i].DrawLeft(g, draw.XYXY(10, y, w-10, y+lh), l, font, n.theme.Color("text"))
+		y += lh
+	}
+	x := w - 5
+	for i := len(n.actions) - 1; i >= 0; i-- {
+		b := n.actions[i]
+		bw, bh := b.PreferredSize(g.FontLookup)
+		x -= bw
+		state.UpdateChild(g, draw.XYWH(x, h-5-bh, bw, bh), b)
+	}
+}
+
+// updateNotifications updates the notifications of a Root, stacked from the bottom right corner upwards.
+func (r *Root) updateNotifications(g *draw.Buffer, state *ui.State, modal bool) {
+	w, h := g.Size()
+	mouse := state.MousePos()
+	y := h - 10
+	notifications := r.notifications[:0]
+	for _, n := range r.notifications {
+		n.theme = r.Theme
+		nw, nh := n.PreferredSize(g.FontLookup)
+		if nw > w-20 {
+			nw = w - 20
+		}
+		hovered := mouse.In(n.bounds)
+		if n.timeout > 0 && !hovered && !n.closing {
+			n.timeout -= state.AnimationSpeed()
+			if n.timeout <= 0 {
+				n.closing = true
+			}
+			state.RequestAnimation()
+		}
+		animate(state, &n.anim, 5, !n.closing)
+		if n.closing && n.anim == 0 {
+			continue
+		}
+		notifications = append(notifications, n)
+		x := w - 10 - int(float32(nw+10)*(1-n.anim))
+		n.bounds = draw.XYWH(x, y-nh, nw, nh)
+		if modal {
+			state.DrawChild(g, n.bounds, n)
+		} else {
+			state.UpdateChild(g, n.bounds, n)
+		}
+		y -= int(float32(nh+10) * n.anim)
+	}
+	for i := len(notifications); i < len(r.notifications); i++ {
+		r.notifications[i] = nil
+	}
+	r.notifications = notifications
+}
+
+// A toastButton is a button in a notification. Unlike Button, it never receives keyboard focus.
+type toastButton struct {
+	n      *Notification
+	icon   string
+	text   string
+	label  text.Text
+	action func(*ui.State)
+	anim   float32
+}
+
+func (b *toastButton) PreferredSize(fonts draw.FontLookup) (int, int) {
+	w, h := b.label.SizeIcon(b.text, b.n.theme.Font("buttonText"), b.icon, 5, fonts)
+	if b.text == "" {
+		return w, h
+	}
+	return w + 16, h + 8
+}
+
+func (b *toastButton) Update(g *draw.Buffer, state *ui.State) {
+	w, h := g.Size()
+	animate(state, &b.anim, 8, state.IsHovered())
+	g.Fill(draw.WH(w, h), draw.Blend(b.n.theme.Color("buttonBackground"), b.n.theme.Color("buttonHovered"), b.anim))
+	b.label.DrawCenteredIcon(g, draw.WH(w, h), b.text, b.n.theme.Font("buttonText"), b.n.theme.Color("buttonText"), b.icon, 5)
+	if state.MouseClick(ui.MouseLeft) {
+		b.n.Dismiss(state)
+		if b.action != nil {
+			b.action(state)
+		}
+		state.RequestUpdate()
+	}
+}
@@ -0,0 +1,353 @@
+package toolkit
+
+import (
+	"io"
+	"sort"
+	"strings"
+)
+
+// A pieceTable stores text as a sequence of pieces of two buffers:
+// the original text, which is never modified, and an append-only buffer that contains all inserted text.
+// Both buffers keep an index of their line breaks, so lines can be found without scanning the text.
+type pieceTable struct {
+	orig   string
+	add    []byte
+	nl     [2][]int // positions of '\n' in orig and add
+	pieces []piece
+	lines  []int // number of line breaks before each piece
+	offs   []int // offset of each piece
+	size   int
+	breaks int
+	crlf   bool // lines are separated by "\r\n"
+}
+
+type piece struct {
+	buf        int // 0: orig, 1: add
+	start, len int
+	nl         int // number of line breaks in this piece
+}
+
+func (p *pieceTable) reset(text string, crlf bool) {
+	p.orig = text
+	p.add = nil
+	p.nl[0] = indexLineBreaks(p.nl[0][:0], text, 0)
+	p.nl[1] = nil
+	p.pieces = p.pieces[:0]
+	if text != "" {
+		p.pieces = append(p.pieces, piece{0, 0, len(text), len(p.nl[0])})
+	}
+	p.crlf = crlf
+	p.rebuild()
+}
+
+func indexLineBreaks(index []int, s string, offset int) []int {
+	for {
+		i := strings.IndexByte(s, '\n')
+		if i < 0 {
+			return index
+		}
+		index = append(index, offset+i)
+		offset += i + 1
+		s = s[i+1:]
+	}
+}
+
+func (p *pieceTable) rebuild() {
+	p.lines = p.lines[:0]
+	p.offs = p.offs[:0]
+	p.size, p.breaks = 0, 0
+	for _, pc := range p.pieces {
+		p.lines = append(p.lines, p.breaks)
+		p.offs = append(p.offs, p.size)
+		p.size += pc.len
+		p.breaks += pc.nl
+	}
+}
+
+func (p *pieceTable) lineCount() int { return p.breaks + 1 }
+
+// lineStart returns the offset of the first character of a line.
+func (p *pieceTable) lineStart(line int) int {
+	if line <= 0 {
+		return 0
+	}
+	if line > p.breaks {
+		return p.size
+	}
+	i := sort.Search(len(p.pieces), func(i int) bool { return p.lines[i]+p.pieces[i].nl >= line })
+	pc := p.pieces[i]
+	index := p.nl[pc.buf]
+	j := sort.SearchInts(index, pc.start) + line - p.lines[i] - 1
+	return p.offs[i] + index[j] - pc.start + 1
+}
+
+// lineEnd returns the offset after the last character of a line, excluding the line break.
+// A "\r" before the "\n" is part of the line break, even if the lines are not separated by "\r\n" consistently.
+func (p *pieceTable) lineEnd(line int) int {
+	if line >= p.breaks {
+		return p.size
+	}
+	end := p.lineStart(line+1) - 1
+	if end > 0 && p.slice(end-1, end) == "\r" {
+		end--
+	}
+	return end
+}
+
+// lineAt returns the line containing the given offset.
+func (p *pieceTable) lineAt(off int) int {
+	return sort.Search(p.breaks, func(i int) bool { return p.lineStart(i+1) > off })
+}
+
+func (p *pieceTable) line(i int) string {
+	return p.slice(p.lineStart(i), p.lineEnd(i))
+}
+
+func (p *pieceTable) String() string {
+	return p.slice(0, p.size)
+}
+
+// find returns the index of the piece containing the given offset.
+func (p *pieceTable) find(off int) int {
+	return sort.Search(len(p.pieces), func(i int) bool { return p.offs[i]+p.pieces[i].len > off })
+}
+
+func (p *pieceTable) text(pc piece, from, to int) string {
+	if pc.buf == 0 {
+		return p.orig[pc.start+from : pc.start+to]
+	}
+	return string(p.add[pc.start+from : pc.start+to])
+}
+
+func (p *pieceTable) slice(a, b int) string {
+	if a >= b {
+		return ""
+	}
+	i := p.find(a)
+	if pc := p.pieces[i]; b <= p.offs[i]+pc.len {
+		return p.text(pc, a-p.offs[i], b-p.offs[i])
+	}
+	var sb strings.Builder
+	sb.Grow(b - a)
+	for ; a < b; i++ {
+		pc := p.pieces[i]
+		to := pc.len
+		if b-p.offs[i] < to {
+			to = b - p.offs[i]
+		}
+		sb.WriteString(p.text(pc, a-p.offs[i], to))
+		a = p.offs[i] + to
+	}
+	return sb.String()
+}
+
+// sub returns a piece containing part of another piece.
+func (p *pieceTable) sub(pc piece, from, to int) piece {
+	index := p.nl[pc.buf]
+	nl := sort.SearchInts(index, pc.start+to) - sort.SearchInts(index, pc.start+from)
+	return piece{pc.buf, pc.start + from, to - from, nl}
+}
+
+// replace replaces the text between the offsets a and b.
+// Only the affected pieces are replaced, the indexes of the following pieces are shifted.
+func (p *pieceTable) replace(a, b int, s string) {
+	i, j := p.find(a), p.find(b)
+	mid := make([]piece, 0, 5)
+	if i > 0 {
+		// the neighbouring pieces are included so they can be merged with the new ones,
+		// typing extends the previous piece instead of adding a new one
+		i--
+		mid = append(mid, p.pieces[i])
+		if i+1 < len(p.pieces) && a > p.offs[i+1] {
+			mid = p.appendPiece(mid, p.sub(p.pieces[i+1], 0, a-p.offs[i+1]))
+		}
+	} else if len(p.pieces) > 0 && a > 0 {
+		mid = append(mid, p.sub(p.pieces[0], 0, a))
+	}
+	if s != "" {
+		mid = p.appendPiece(mid, p.addText(s))
+	}
+	if j < len(p.pieces) {
+		mid = p.appendPiece(mid, p.sub(p.pieces[j], b-p.offs[j], p.pieces[j].len))
+		j++
+		if j < len(p.pieces) {
+			mid = p.appendPiece(mid, p.pieces[j])
+			j++
+		}
+	}
+	lines, off := p.breaks, p.size
+	if i < len(p.pieces) {
+		lines, off = p.lines[i], p.offs[i]
+	}
+	oldBreaks := p.breaks - lines
+	if j < len(p.pieces) {
+		oldBreaks = p.lines[j] - lines
+	}
+
+	n := len(mid) - (j - i)
+	if n > 0 {
+		p.pieces = append(p.pieces, make([]piece, n)...)
+		p.lines = append(p.lines, make([]int, n)...)
+		p.offs = append(p.offs, make([]int, n)...)
+	}
+	copy(p.pieces[j+n:], p.pieces[j:])
+	copy(p.lines[j+n:], p.lines[j:])
+	copy(p.offs[j+n:], p.offs[j:])
+	if n < 0 {
+		p.pieces = p.pieces[:len(p.pieces)+n]
+		p.lines = p.lines[:len(p.lines)+n]
+		p.offs = p.offs[:len(p.offs)+n]
+	}
+	newBreaks := 0
+	for k, pc := range mid {
+		p.pieces[i+k] = pc
+		p.lines[i+k] = lines + newBreaks
+		p.offs[i+k] = off
+		off += pc.len
+		newBreaks += pc.nl
+	}
+	ds, db := len(s)-(b-a), newBreaks-oldBreaks
+	for k := i + len(mid); k < len(p.pieces); k++ {
+		p.lines[k] += db
+		p.offs[k] += ds
+	}
+	p.size += ds
+	p.breaks += db
+}
+
+// An edit replaces the text between two offsets.
+type edit struct {
+	from, to int
+	text     string
+}
+
+// replaceAll applies several edits at once, which is faster than calling replace for each of them.
+// The edits must be sorted and must not overlap, their offsets refer to the text before any edit is applied.
+func (p *pieceTable) replaceAll(edits []edit) {
+	pieces := make([]piece, 0, len(p.pieces)+2*len(edits))
+	off := 0
+	keep := func(to int) {
+		for off < to {
+			i := p.find(off)
+			end := p.offs[i] + p.pieces[i].len
+			if end > to {
+				end = to
+			}
+			pieces = p.appendPiece(pieces, p.sub(p.pieces[i], off-p.offs[i], end-p.offs[i]))
+			off = end
+		}
+	}
+	for _, e := range edits {
+		keep(e.from)
+		if e.text != "" {
+			pieces = p.appendPiece(pieces, p.addText(e.text))
+		}
+		off = e.to
+	}
+	keep(p.size)
+	p.pieces = pieces
+	p.rebuild()
+}
+
+// addText appends s to the add buffer and returns a piece containing it.
+func (p *pieceTable) addText(s string) piece {
+	start := len(p.add)
+	p.add = append(p.add, s...)
+	nl := len(p.nl[1])
+	p.nl[1] = indexLineBreaks(p.nl[1], s, start)
+	return piece{1, start, len(s), len(p.nl[1]) - nl}
+}
+
+// appendPiece appends a piece to a list, empty pieces are dropped and adjacent pieces are merged.
+func (p *pieceTable) appendPiece(pieces []piece, pc piece) []piece {
+	if pc.len == 0 {
+		return pieces
+	}
+	if l := len(pieces) - 1; l >= 0 && pieces[l].buf == pc.buf && pieces[l].start+pieces[l].len == pc.start {
+		pieces[l].len += pc.len
+		pieces[l].nl += pc.nl
+		return pieces
+	}
+	return append(pieces, pc)
+}
+
+// index returns the offset of the first occurrence of s at or after from, or -1 if there is none.
+// The pieces are searched one at a time, so the text does not have to be copied.
+func (p *pieceTable) index(s string, from int) int {
+	if s == "" {
+		return from
+	}
+	carry := "" // the end of the text that was already searched, shorter than s
+	for i := p.find(from); i < len(p.pieces); i++ {
+		pc := p.pieces[i]
+		chunk := p.text(pc, from-p.offs[i], pc.len)
+		if carry != "" {
+			// a match starting in an earlier piece
+			head := chunk
+			if len(head) >= len(s) {
+				head = head[:len(s)-1]
+			}
+			if j := strings.Index(carry+head, s); j >= 0 {
+				return from - len(carry) + j
+			}
+		}
+		if j := strings.Index(chunk, s); j >= 0 {
+			return from + j
+		}
+		if len(chunk) >= len(s)-1 {
+			carry = chunk[len(chunk)-len(s)+1:]
+		} else {
+			carry += chunk
+			if len(carry) >= len(s) {
+				carry = carry[len(carry)-len(s)+1:]
+			}
+		}
+		from = p.offs[i] + pc.len
+	}
+	return -1
+}
+
+func (p *pieceTable) writeTo(w io.Writer) error {
+	for _, pc := range p.pieces {
+		if _, err := io.WriteString(w, p.text(pc, 0, pc.len)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reader returns a reader for the current contents of the piece table.
+// Later changes to the piece table will not affect the reader.
+func (p *pieceTable) reader() io.Reader {
+	return &pieceReader{p.orig, p.add[:len(p.add):len(p.add)], append([]piece(nil), p.pieces...), 0}
+}
+
+type pieceReader struct {
+	orig   string
+	add    []byte
+	pieces []piece
+	pos    int
+}
+
+func (r *pieceReader) Read(b []byte) (int, error) {
+	n := 0
+	for n < len(b) && len(r.pieces) > 0 {
+		pc := r.pieces[0]
+		var c int
+		if pc.buf == 0 {
+			c = copy(b[n:], r.orig[pc.start+r.pos:pc.start+pc.len])
+		} else {
+			c = copy(b[n:], r.add[pc.start+r.pos:pc.start+pc.len])
+		}
+		n += c
+		r.pos += c
+		if r.pos == pc.len {
+			r.pieces = r.pieces[1:]
+			r.pos = 0
+		}
+	}
+	if n == 0 && len(b) > 0 {
+		return 0, io.EOF
+	}
+	return n, nil
+}
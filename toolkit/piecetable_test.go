@@ -0,0 +1,215 @@
+package toolkit
+
+import (
+	"strings"
+	"testing"
+)
+
+// split returns a piece table containing text, with every part stored in its own piece.
+func split(parts ...string) *pieceTable {
+	var p pieceTable
+	p.reset(parts[0], false)
+	for _, s := range parts[1:] {
+		// insert in front of a placeholder so the new piece is not merged with the previous one
+		p.replace(p.size, p.size, "#")
+		p.replace(p.size-1, p.size-1, s)
+		p.replace(p.size-1, p.size, "")
+	}
+	return &p
+}
+
+func checkIndexes(t *testing.T, p *pieceTable) {
+	t.Helper()
+	size, breaks := 0, 0
+	for i, pc := range p.pieces {
+		if p.offs[i] != size || p.lines[i] != breaks {
+			t.Fatalf("piece %d: index (%d, %d), want (%d, %d)", i, p.offs[i], p.lines[i], size, breaks)
+		}
+		if n := strings.Count(p.text(pc, 0, pc.len), "\n"); n != pc.nl {
+			t.Fatalf("piece %d: %d line breaks, want %d", i, pc.nl, n)
+		}
+		size += pc.len
+		breaks += pc.nl
+	}
+	if p.size != size || p.breaks != breaks {
+		t.Fatalf("size (%d, %d), want (%d, %d)", p.size, p.breaks, size, breaks)
+	}
+}
+
+func TestPieceTableLineStart(t *testing.T) {
+	tests := []struct {
+		parts []string
+		want  []int
+	}{
+		{[]string{""}, []int{0}},
+		{[]string{"abc"}, []int{0}},
+		{[]string{"a\nb\nc"}, []int{0, 2, 4}},
+		{[]string{"a\n", "b\n", "c"}, []int{0, 2, 4}},
+		{[]string{"a", "\nb", "\n\nc\n"}, []int{0, 2, 4, 5, 7}},
+		{[]string{"\n", "\n", "\n"}, []int{0, 1, 2, 3}},
+	}
+	for _, tt := range tests {
+		p := split(tt.parts...)
+		checkIndexes(t, p)
+		if p.lineCount() != len(tt.want) {
+			t.Errorf("%q: %d lines, want %d", tt.parts, p.lineCount(), len(tt.want))
+			continue
+		}
+		for i, want := range tt.want {
+			if got := p.lineStart(i); got != want {
+				t.Errorf("%q: lineStart(%d) = %d, want %d", tt.parts, i, got, want)
+			}
+			if got := p.lineAt(want); got != i {
+				t.Errorf("%q: lineAt(%d) = %d, want %d", tt.parts, want, got, i)
+			}
+		}
+	}
+}
+
+func TestPieceTableSub(t *testing.T) {
+	var p pieceTable
+	p.reset("ab\ncd\n\nef", false)
+	pc := p.pieces[0]
+	tests := []struct {
+		from, to int
+		nl       int
+	}{
+		{0, 2, 0},
+		{0, 3, 1},
+		{2, 3, 1},
+		{3, 6, 1},
+		{2, 7, 3},
+		{7, 9, 0},
+		{0, 9, 3},
+		{4, 4, 0},
+	}
+	for _, tt := range tests {
+		s := p.sub(pc, tt.from, tt.to)
+		if s.start != tt.from || s.len != tt.to-tt.from || s.nl != tt.nl {
+			t.Errorf("sub(%d, %d) = %+v, want %d line breaks", tt.from, tt.to, s, tt.nl)
+		}
+	}
+}
+
+func TestPieceTableReplace(t *testing.T) {
+	tests := []struct {
+		parts []string
+		a, b  int
+		s     string
+	}{
+		{[]string{""}, 0, 0, "x"},
+		{[]string{"abc"}, 1, 2, "x\ny"},
+		{[]string{"ab", "cd", "ef"}, 1, 5, ""},
+		{[]string{"ab", "cd", "ef"}, 2, 4, "\n"},
+		{[]string{"a\nb", "c\nd", "e\nf"}, 2, 8, "x"},
+		{[]string{"ab", "cd", "ef"}, 0, 6, ""},
+		{[]string{"ab", "cd", "ef"}, 6, 6, "g\n"},
+		{[]string{"ab", "cd", "ef"}, 0, 0, "\n"},
+		{[]string{"a\n", "\nb"}, 2, 2, "x"},
+	}
+	for _, tt := range tests {
+		p := split(tt.parts...)
+		orig := strings.Join(tt.parts, "")
+		p.replace(tt.a, tt.b, tt.s)
+		want := orig[:tt.a] + tt.s + orig[tt.b:]
+		if got := p.String(); got != want {
+			t.Errorf("%q: replace(%d, %d, %q) = %q, want %q", tt.parts, tt.a, tt.b, tt.s, got, want)
+			continue
+		}
+		checkIndexes(t, p)
+		lines := strings.Split(want, "\n")
+		for i, l := range lines {
+			if got := p.line(i); got != l {
+				t.Errorf("%q: replace(%d, %d, %q): line %d = %q, want %q", tt.parts, tt.a, tt.b, tt.s, i, got, l)
+			}
+		}
+	}
+}
+
+func TestPieceTableTyping(t *testing.T) {
+	var p pieceTable
+	p.reset("ab", false)
+	for i, c := range "xyz" {
+		p.replace(1+i, 1+i, string(c))
+	}
+	if got := p.String(); got != "axyzb" {
+		t.Fatalf("got %q", got)
+	}
+	// the inserted characters are stored in a single piece
+	if len(p.pieces) != 3 {
+		t.Errorf("%d pieces, want 3", len(p.pieces))
+	}
+	checkIndexes(t, &p)
+}
+
+func TestPieceTableReplaceAll(t *testing.T) {
+	p := split("a\n", "b\nc", "\nd")
+	p.replaceAll([]edit{{0, 0, "  "}, {2, 2, "  "}, {3, 4, ""}, {6, 6, "\t"}})
+	if got, want := p.String(), "  a\n  bc\n\td"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	checkIndexes(t, p)
+}
+
+func TestPieceTableCRLF(t *testing.T) {
+	var p pieceTable
+	p.reset("ab\r\ncd\r\n\r\nef", true)
+	want := []string{"ab", "cd", "", "ef"}
+	if p.lineCount() != len(want) {
+		t.Fatalf("%d lines, want %d", p.lineCount(), len(want))
+	}
+	for i, w := range want {
+		if got := p.line(i); got != w {
+			t.Errorf("line %d = %q, want %q", i, got, w)
+		}
+	}
+	// a line break split across pieces
+	p.replace(3, 3, "x\r")
+	if got := p.line(0); got != "ab\rx" {
+		t.Errorf("line 0 = %q, want %q", got, "ab\rx")
+	}
+	if got := p.lineEnd(0); got != 4 {
+		t.Errorf("lineEnd(0) = %d, want 4", got)
+	}
+	if got := p.lineStart(1); got != 6 {
+		t.Errorf("lineStart(1) = %d, want 6", got)
+	}
+	checkIndexes(t, &p)
+}
+
+func TestPieceTableMixedLineBreaks(t *testing.T) {
+	var p pieceTable
+	p.reset("ab\r\ncd\nef\r\n", false)
+	want := []string{"ab", "cd", "ef", ""}
+	for i, w := range want {
+		if got := p.line(i); got != w {
+			t.Errorf("line %d = %q, want %q", i, got, w)
+		}
+	}
+}
+
+func TestPieceTableIndex(t *testing.T) {
+	p := split("abc", "def", "g", "hij")
+	tests := []struct {
+		s    string
+		from int
+		want int
+	}{
+		{"abc", 0, 0},
+		{"cd", 0, 2},
+		{"cdefgh", 0, 2},
+		{"efg", 0, 4},
+		{"fgh", 0, 5},
+		{"j", 0, 9},
+		{"abc", 1, -1},
+		{"gh", 6, 6},
+		{"gh", 7, -1},
+		{"x", 0, -1},
+		{"", 3, 3},
+	}
+	for _, tt := range tests {
+		if got := p.index(tt.s, tt.from); got != tt.want {
+			t.Errorf("index(%q, %d) = %d, want %d", tt.s, tt.from, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package toolkit
+
+import (
+	"context"
+	"image"
+	"sync"
+
+	"github.com/jfreymuth/ui"
+	"github.com/jfreymuth/ui/draw"
+	"github.com/jfreymuth/ui/text"
+)
+
+// ShowProgressDialog runs work on a new goroutine and shows a dialog with a progress bar until it returns.
+// work may call report from any goroutine, a negative progress shows an indeterminate progress bar.
+// The context is cancelled if the user clicks the cancel button or presses escape.
+// If work returns an error other than context.Canceled, it is shown with ShowErrorDialog.
+func ShowProgressDialog(state *ui.State, title string, work func(ctx context.Context, report func(progress float32, msg string)) error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &progressView{theme: DefaultTheme, title: title, progress: -1, wake: state.Waker()}
+	p.cancel = NewButton("Cancel", func(*ui.State) {
+		cancel()
+		p.mu.Lock()
+		p.msg, p.cancelled = "Cancelling...", true
+		p.mu.Unlock()
+	})
+	p.frame = NewFrame("", title, &Container{
+		Center: NewPadding(p, 10),
+		Bottom: NewBar(-1, p.cancel),
+	}, DefaultTheme.Color("titleBackground"))
+	p.frame.Cancel = p.cancel.Action
+	state.OpenDialog(p.frame)
+	go func() {
+		err := work(ctx, p.report)
+		cancel()
+		p.mu.Lock()
+		p.done, p.err = true, err
+		p.mu.Unlock()
+		p.changed()
+	}()
+}
+
+type progressView struct {
+	theme  *Theme
+	title  string
+	frame  *Frame
+	cancel *Button
+	text   text.Text
+	anim   float32
+	wake   func()
+
+	mu        sync.Mutex
+	progress  float32
+	msg       string
+	cancelled bool
+	done      bool
+	err       error
+}
+
+func (p *progressView) report(progress float32, msg string) {
+	p.mu.Lock()
+	p.progress = progress
+	// after the user cancelled, the message keeps saying so
+	if !p.cancelled {
+		p.msg = msg
+	}
+	p.mu.Unlock()
+	p.changed()
+}
+
+// changed requests an update after the work goroutine changed the state of the dialog.
+func (p *progressView) changed() {
+	if p.wake != nil {
+		p.wake()
+	}
+}
+
+func (p *progressView) SetTheme(theme *Theme) { p.theme = theme }
+
+func (p *progressView) PreferredSize(fonts draw.FontLookup) (int, int) {
+	return 300, fonts.Metrics(p.theme.Font("text")).LineHeight() + 18
+}
+
+func (p *progressView) Update(g *draw.Buffer, state *ui.State) {
+	p.mu.Lock()
+	progress, msg, done, err := p.progress, p.msg, p.done, p.err
+	p.mu.Unlock()
+	// the dialog is only closed if it is the topmost one, otherwise the dialog above it would be closed
+	if r, ok := state.Root().(*Root); done && (!ok || r.Dialog() == p.frame) {
+		state.CloseDialog()
+		if err != nil && err != context.Canceled {
+			ShowErrorDialog(state, p.title, err.Error(), "Close")
+		}
+		return
+	}
+	if p.wake == nil {
+		// the backend can not be woken up by the work goroutine, so the dialog has to check for changes regularly
+		state.RequestAnimation()
+	}
+	w, h := g.Size()
+	th := g.FontLookup.Metrics(p.theme.Font("text")).LineHeight()
+	p.text.DrawLeft(g, draw.WH(w, th), msg, p.theme.Font("text"), p.theme.Color("text"))
+	bar := draw.XYXY(0, h-8, w, h)
+	g.Fill(bar, p.theme.Color("veil"))
+	if progress < 0 {
+		drawIndeterminate(g, bar, &p.anim, state, p.theme.Color("selection"))
+	} else {
+		if progress > 1 {
+			progress = 1
+		}
+		g.Fill(draw.XYWH(0, bar.Min.Y, int(progress*float32(w)), bar.Dy()), p.theme.Color("selection"))
+	}
+}
+
+// drawIndeterminate draws an indeterminate progress bar, a block moving back and forth.
+func drawIndeterminate(g *draw.Buffer, r image.Rectangle, anim *float32, state *ui.State, c draw.Color) {
+	*anim += state.AnimationSpeed() / 2
+	if *anim >= 2 {
+		*anim -= 2
+	}
+	x := *anim
+	if x > 1 {
+		x = 2 - x
+	}
+	bw := r.Dx() / 4
+	g.Fill(draw.XYWH(r.Min.X+int(x*float32(r.Dx()-bw)), r.Min.Y, bw, r.Dy()), c)
+	state.RequestAnimation()
+}
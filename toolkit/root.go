@@ -8,16 +8,27 @@ import (
 )
 
 type Root struct {
-	Content ui.Component
-	Dialog  ui.Component
-	Theme   *Theme
-	popups  []*popup
+	Content   ui.Component
+	Theme     *Theme
+	Shortcuts Shortcuts
+	// If KeepDialogOnEscape is false, pressing escape closes the topmost dialog, unless the focused component uses the key.
+	KeepDialogOnEscape bool
+	dialogs            []dialog
+	popups             []*popup
+
+	notifications []*Notification
+}
+
+type dialog struct {
+	ui.Component
+	fade float32
 }
 
 type popup struct {
 	ui.Component
-	bounds image.Rectangle
-	closed bool
+	bounds   image.Rectangle
+	closed   bool
+	nonModal bool
 }
 
 func NewRoot(content ui.Component) *Root {
@@ -27,19 +38,38 @@ func NewRoot(content ui.Component) *Root {
 func (r *Root) SetTheme(theme *Theme) {
 	r.Theme = theme
 	SetTheme(r.Content, theme)
-	SetTheme(r.Dialog, theme)
+	for _, d := range r.dialogs {
+		SetTheme(d.Component, theme)
+	}
+}
+
+// Dialog returns the topmost dialog, or nil if no dialog is open.
+func (r *Root) Dialog() ui.Component {
+	if len(r.dialogs) == 0 {
+		return nil
+	}
+	return r.dialogs[len(r.dialogs)-1].Component
 }
 
-func (r *Root) OpenDialog(dialog ui.Component) {
-	r.Dialog = dialog
+func (r *Root) OpenDialog(d ui.Component) {
+	r.dialogs = append(r.dialogs, dialog{Component: d})
 }
 
 func (r *Root) CloseDialog() {
-	r.Dialog = nil
+	if l := len(r.dialogs) - 1; l >= 0 {
+		r.dialogs[l] = dialog{}
+		r.dialogs = r.dialogs[:l]
+	}
 }
 
 func (r *Root) OpenPopup(bounds image.Rectangle, p ui.Component) ui.Popup {
-	popup := &popup{p, bounds, false}
+	popup := &popup{p, bounds, false, false}
+	r.popups = append(r.popups, popup)
+	return popup
+}
+
+func (r *Root) OpenNonModalPopup(bounds image.Rectangle, p ui.Component) ui.Popup {
+	popup := &popup{p, bounds, false, true}
 	r.popups = append(r.popups, popup)
 	return popup
 }
@@ -52,7 +82,29 @@ func (r *Root) ClosePopups() {
 }
 
 func (r *Root) HasPopups() bool {
-	return r.popups != nil
+	for _, p := range r.popups {
+		if !p.closed && !p.nonModal {
+			return true
+		}
+	}
+	return false
+}
+
+// prunePopups removes closed popups, so the list does not grow while popups are repeatedly opened and closed.
+func (r *Root) prunePopups() {
+	open := r.popups[:0]
+	for _, p := range r.popups {
+		if !p.closed {
+			open = append(open, p)
+		}
+	}
+	for i := len(open); i < len(r.popups); i++ {
+		r.popups[i] = nil
+	}
+	if len(open) == 0 {
+		open = nil
+	}
+	r.popups = open
 }
 
 func (p *popup) Close()       { p.closed = true }
@@ -66,41 +118,57 @@ func (r *Root) Update(g *draw.Buffer, state *ui.State) {
 	state.SetRoot(r)
 	w, h := g.Size()
 	g.Fill(draw.WH(w, h), r.Theme.Color("background"))
-	if r.Dialog == nil {
-		if len(r.popups) == 0 {
-			state.UpdateChild(g, draw.WH(w, h), r.Content)
-		} else {
-			state.DrawChild(g, draw.WH(w, h), r.Content)
-		}
+	state.HandleHotKeys()
+	popups, top := r.HasPopups(), r.Dialog()
+	if len(r.dialogs) == 0 {
+		r.updateChild(g, state, draw.WH(w, h), r.Content)
 	} else {
 		state.DrawChild(g, draw.WH(w, h), r.Content)
 		g.Fill(draw.WH(w, h), r.Theme.Color("veil"))
-		dw, dh := r.Dialog.PreferredSize(g.FontLookup)
-		if dw > w*7/8 {
-			dw = w * 7 / 8
-		}
-		if dh > h*7/8 {
-			dh = h * 7 / 8
+		for i := range r.dialogs {
+			d := &r.dialogs[i]
+			dw, dh := d.PreferredSize(g.FontLookup)
+			if dw > w*7/8 {
+				dw = w * 7 / 8
+			}
+			if dh > h*7/8 {
+				dh = h * 7 / 8
+			}
+			bounds := draw.XYWH((w-dw)/2, (h-dh)/2, dw, dh)
+			if f, ok := d.Component.(*Frame); ok {
+				bounds = f.dialogBounds(bounds, draw.WH(w, h))
+			}
+			// new dialogs fade in
+			animate(state, &d.fade, 8, true)
+			g.PushOpacity(d.fade)
+			if i == len(r.dialogs)-1 {
+				r.updateChild(g, state, bounds, d.Component)
+				g.Pop()
+			} else {
+				state.DrawChild(g, bounds, d.Component)
+				g.Pop()
+				g.Fill(draw.WH(w, h), r.Theme.Color("veil"))
+			}
 		}
-		dx, dy := (w-dw)/2, (h-dh)/2
-		if len(r.popups) == 0 {
-			state.UpdateChild(g, draw.XYWH(dx, dy, dw, dh), r.Dialog)
-		} else {
-			state.DrawChild(g, draw.XYWH(dx, dy, dw, dh), r.Dialog)
+	}
+	if !popups && !r.HasPopups() {
+		// escape and shortcuts only receive the key events that were not handled by the focused component
+		if top != nil && top == r.Dialog() && !r.KeepDialogOnEscape {
+			r.escape(state)
 		}
+		r.Shortcuts.handle(state, top == nil)
+	}
+	if r.notifications != nil {
+		r.updateNotifications(g, state, r.HasPopups())
 	}
 	if r.popups != nil {
-		allClosed := true
 		for _, p := range r.popups {
 			if !p.closed {
 				state.UpdateChild(g, p.bounds, p.Component)
-				allClosed = false
 			}
 		}
-		if allClosed {
-			r.popups = nil
-		}
-		if state.MouseButtonDown(ui.MouseLeft) || state.MouseButtonDown(ui.MouseRight) {
+		r.prunePopups()
+		if r.HasPopups() && (state.MouseButtonDown(ui.MouseLeft) || state.MouseButtonDown(ui.MouseRight)) {
 			state.ClosePopups()
 			state.RequestRefocus()
 		}
@@ -113,3 +181,42 @@ func (r *Root) Update(g *draw.Buffer, state *ui.State) {
 		}
 	}
 }
+
+// escape closes the topmost dialog if escape was pressed.
+func (r *Root) escape(state *ui.State) {
+	for _, k := range state.PeekKeyPresses() {
+		if k == ui.KeyEscape {
+			state.ConsumeKeyPress(k)
+			if f, ok := r.Dialog().(*Frame); ok && f.Cancel != nil {
+				f.Cancel(state)
+			} else {
+				state.CloseDialog()
+			}
+			return
+		}
+	}
+}
+
+// updateChild updates the content or the dialog.
+// While a modal popup is open, the component is only drawn.
+// If the mouse is above a non-modal popup or a notification, the component will not receive mouse events.
+func (r *Root) updateChild(g *draw.Buffer, state *ui.State, bounds image.Rectangle, c ui.Component) {
+	if r.HasPopups() {
+		state.DrawChild(g, bounds, c)
+		return
+	}
+	mouse := state.MousePos()
+	for _, p := range r.popups {
+		if !p.closed && mouse.In(p.bounds) {
+			state.UpdateChildWithoutMouse(g, bounds, c)
+			return
+		}
+	}
+	for _, n := range r.notifications {
+		if mouse.In(n.bounds) {
+			state.UpdateChildWithoutMouse(g, bounds, c)
+			return
+		}
+	}
+	state.UpdateChild(g, bounds, c)
+}
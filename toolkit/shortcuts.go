@@ -0,0 +1,112 @@
+package toolkit
+
+import (
+	"errors"
+
+	"github.com/jfreymuth/ui"
+)
+
+// Shortcuts maps keyboard shortcuts to actions.
+// The shortcuts of a Root are handled after its content is updated,
+// only key events that the focused component did not handle can activate a shortcut.
+type Shortcuts struct {
+	entries []shortcut
+}
+
+type shortcut struct {
+	ui.Shortcut
+	scope  ui.Component
+	action func(*ui.State)
+	item   *MenuItem
+}
+
+// Add registers a shortcut that is active in the whole window, unless a dialog is open.
+// An error is returned if the shortcut can not be parsed or is already in use.
+func (s *Shortcuts) Add(sc string, action func(*ui.State)) error {
+	return s.add(sc, shortcut{action: action})
+}
+
+// AddScoped registers a shortcut that is only active while scope or one of its descendants has the keyboard focus.
+// Scoped shortcuts take precedence over window-wide shortcuts.
+func (s *Shortcuts) AddScoped(sc string, scope ui.Component, action func(*ui.State)) error {
+	return s.add(sc, shortcut{scope: scope, action: action})
+}
+
+// AddMenuItem registers a window-wide shortcut that activates a menu item.
+// The item's Accelerator is set to the shortcut.
+// The shortcut does nothing while the item is disabled. The menu's AboutToShow function is not called for shortcuts,
+// so if it changes the Disabled field, the field may be out of date when the shortcut is pressed.
+func (s *Shortcuts) AddMenuItem(sc string, item *MenuItem) error {
+	if err := s.add(sc, shortcut{item: item}); err != nil {
+		return err
+	}
+	item.Accelerator = s.entries[len(s.entries)-1].String()
+	return nil
+}
+
+func (s *Shortcuts) add(sc string, e shortcut) error {
+	parsed, err := ui.ParseShortcut(sc)
+	if err != nil {
+		return err
+	}
+	e.Shortcut = parsed
+	for _, o := range s.entries {
+		if o.Shortcut == e.Shortcut && o.scope == e.scope {
+			return errors.New("toolkit: shortcut " + parsed.String() + " is already in use")
+		}
+	}
+	s.entries = append(s.entries, e)
+	return nil
+}
+
+// Remove removes all actions registered for a shortcut.
+func (s *Shortcuts) Remove(sc string) {
+	parsed, err := ui.ParseShortcut(sc)
+	if err != nil {
+		return
+	}
+	entries := s.entries[:0]
+	for _, e := range s.entries {
+		if e.Shortcut != parsed {
+			entries = append(entries, e)
+		}
+	}
+	s.entries = entries
+}
+
+func (s *Shortcuts) handle(state *ui.State, global bool) {
+	// ConsumeKeyPress modifies the slice returned by PeekKeyPresses, so a copy is needed
+	keys := append([]ui.Key(nil), state.PeekKeyPresses()...)
+	for _, k := range keys {
+		if e := s.find(state, k, global); e != nil {
+			state.ConsumeKeyPress(k)
+			e.activate(state)
+			state.RequestUpdate()
+		}
+	}
+}
+
+func (s *Shortcuts) find(state *ui.State, k ui.Key, global bool) *shortcut {
+	var found *shortcut
+	for i := range s.entries {
+		e := &s.entries[i]
+		if e.Key != k || !state.HasExactModifiers(e.Modifiers) {
+			continue
+		}
+		if e.scope != nil && state.HasKeyboardFocusWithin(e.scope) {
+			return e
+		}
+		if e.scope == nil && global && (e.item == nil || !e.item.Disabled) {
+			found = e
+		}
+	}
+	return found
+}
+
+func (e *shortcut) activate(state *ui.State) {
+	if e.item != nil {
+		e.item.activate(state)
+	} else if e.action != nil {
+		e.action(state)
+	}
+}
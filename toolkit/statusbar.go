@@ -0,0 +1,144 @@
+package toolkit
+
+import (
+	"time"
+
+	"github.com/jfreymuth/ui"
+	"github.com/jfreymuth/ui/draw"
+	"github.com/jfreymuth/ui/text"
+)
+
+// A StatusBar shows information in a row of segments, typically at the bottom of a window.
+type StatusBar struct {
+	Theme    *Theme
+	Segments []*StatusSegment
+	// Refresh is called at the start of every update, it can be used to update the segments.
+	Refresh func(*ui.State)
+	msg     string
+	msgText text.Text
+	msgTime float32
+}
+
+// A StatusSegment is a part of a StatusBar.
+type StatusSegment struct {
+	Text string
+	Icon string
+	// Progress is shown as a small progress bar if it is between 0 and 1.
+	// Negative values hide the progress bar.
+	Progress float32
+	// Width is the minimum width of the segment.
+	Width int
+	// Stretching segments share the space that is not used by other segments.
+	// Transient messages are shown in the first stretching segment.
+	Stretch bool
+	text    text.Text
+}
+
+func NewStatusBar() *StatusBar {
+	return &StatusBar{Theme: DefaultTheme}
+}
+
+func (s *StatusBar) SetTheme(theme *Theme) { s.Theme = theme }
+
+// AddSegment adds a segment showing text.
+func (s *StatusBar) AddSegment(text string, stretch bool) *StatusSegment {
+	seg := &StatusSegment{Text: text, Stretch: stretch, Progress: -1}
+	s.Segments = append(s.Segments, seg)
+	return seg
+}
+
+// ShowMessage shows a message in place of the first stretching segment, until the timeout has passed.
+func (s *StatusBar) ShowMessage(state *ui.State, msg string, timeout time.Duration) {
+	s.msg = msg
+	s.msgTime = float32(timeout.Seconds())
+	state.RequestUpdate()
+}
+
+// Message returns the transient message that is currently shown, if any.
+func (s *StatusBar) Message() string {
+	return s.msg
+}
+
+const statusProgressWidth = 60
+
+func (s *StatusBar) PreferredSize(fonts draw.FontLookup) (int, int) {
+	w, h := 0, fonts.Metrics(s.Theme.Font("text")).LineHeight()
+	for _, seg := range s.Segments {
+		w += s.segmentWidth(seg, fonts)
+	}
+	return w, h + 6
+}
+
+func (s *StatusBar) segmentWidth(seg *StatusSegment, fonts draw.FontLookup) int {
+	w, _ := seg.text.SizeIcon(seg.Text, s.Theme.Font("text"), seg.Icon, 3, fonts)
+	if seg.Progress >= 0 && seg.Progress <= 1 {
+		if w > 0 {
+			w += 5
+		}
+		w += statusProgressWidth
+	}
+	if w < seg.Width {
+		w = seg.Width
+	}
+	return w + 11
+}
+
+func (s *StatusBar) Update(g *draw.Buffer, state *ui.State) {
+	if s.Refresh != nil {
+		s.Refresh(state)
+	}
+	if s.msg != "" {
+		s.msgTime -= state.AnimationSpeed()
+		if s.msgTime <= 0 {
+			s.msg = ""
+		} else {
+			state.RequestAnimation()
+		}
+	}
+	w, h := g.Size()
+	g.Fill(draw.WH(w, h), s.Theme.Color("altBackground"))
+	ws := make([]int, len(s.Segments))
+	stretch := 0
+	rest := w
+	for i, seg := range s.Segments {
+		ws[i] = s.segmentWidth(seg, g.FontLookup)
+		rest -= ws[i]
+		if seg.Stretch {
+			stretch++
+		}
+	}
+	font := s.Theme.Font("text")
+	color := s.Theme.Color("text")
+	msg := s.msg != ""
+	x := 0
+	for i, seg := range s.Segments {
+		sw := ws[i]
+		if seg.Stretch && rest > 0 {
+			sw += rest / stretch
+			rest -= rest / stretch
+			stretch--
+		}
+		if i > 0 {
+			g.Fill(draw.XYWH(x, 3, 1, h-6), s.Theme.Color("separator"))
+		}
+		r := draw.XYXY(x+6, 0, x+sw-5, h)
+		if msg && seg.Stretch {
+			s.msgText.DrawLeft(g, r, s.msg, font, color)
+			msg = false
+		} else {
+			if seg.Progress >= 0 && seg.Progress <= 1 {
+				py := (h - 6) / 2
+				g.Fill(draw.XYWH(r.Max.X-statusProgressWidth, py, statusProgressWidth, 6), s.Theme.Color("veil"))
+				g.Fill(draw.XYWH(r.Max.X-statusProgressWidth, py, int(seg.Progress*statusProgressWidth), 6), s.Theme.Color("selection"))
+				r.Max.X -= statusProgressWidth + 5
+			}
+			seg.text.DrawLeftIcon(g, r, seg.Text, font, color, seg.Icon, 3)
+		}
+		x += sw
+	}
+	if msg {
+		// no stretching segment, draw the message over the whole status bar
+		g.Fill(draw.WH(w, h), s.Theme.Color("altBackground"))
+		s.msgText.DrawLeft(g, draw.XYXY(6, 0, w-5, h), s.msg, font, color)
+	}
+}
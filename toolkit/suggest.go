@@ -0,0 +1,91 @@
+package toolkit
+
+import (
+	"image"
+
+	"github.com/jfreymuth/ui"
+	"github.com/jfreymuth/ui/draw"
+)
+
+// suggestions shows a list in a popup below a component, without taking the keyboard focus from that component.
+type suggestions struct {
+	List
+	sv     *ScrollView
+	popup  ui.Popup
+	scroll bool
+}
+
+// show opens the popup below the current component, replacing any previously shown suggestions.
+// If the user clicks an item, accept will be called and the keyboard focus will be given to owner.
+func (s *suggestions) show(g *draw.Buffer, state *ui.State, owner ui.Component, items []ListItem, theme *Theme, accept func(*ui.State, ListItem)) {
+	s.close()
+	if len(items) == 0 {
+		return
+	}
+	if s.sv == nil {
+		s.sv = NewScrollView(s)
+	}
+	s.sv.SetTheme(theme)
+	s.Items, s.Selected, s.noMenu = items, 0, true
+	s.Changed = func(state *ui.State, item ListItem) {
+		accept(state, item)
+		state.SetKeyboardFocus(owner)
+	}
+	w, h := g.Size()
+	bg := &menuBackground{s.sv, theme}
+	pw, ph := bg.size(s.List.PreferredSize(g.FontLookup))
+	s.popup = state.OpenNonModalPopup(popupBounds(state, w, h, pw, ph), bg)
+}
+
+func (s *suggestions) isOpen() bool { return isOpen(s.popup) }
+
+func (s *suggestions) close() {
+	if s.popup != nil {
+		s.popup.Close()
+		s.popup = nil
+	}
+}
+
+// move moves the highlighted item up or down.
+func (s *suggestions) move(d int) {
+	s.Selected += d
+	if s.Selected < 0 {
+		s.Selected = 0
+	} else if s.Selected >= len(s.Items) {
+		s.Selected = len(s.Items) - 1
+	}
+	s.scroll = true
+}
+
+func (s *suggestions) selected() ListItem { return s.Items[s.Selected] }
+
+func (s *suggestions) Update(g *draw.Buffer, state *ui.State) {
+	s.List.Update(g, state)
+	if s.scroll && len(s.Items) > 0 {
+		_, h := s.Items[0].text.Size(s.Items[0].Text, s.Theme.Font("text"), g.FontLookup)
+		state.RequestVisible(draw.XYWH(0, s.Selected*h, 1, h))
+		s.scroll = false
+	}
+}
+
+// popupBounds returns the bounds of a popup of size pw, ph attached to a component of size w, h.
+// The popup is placed below the component if there is enough space, otherwise above it.
+func popupBounds(state *ui.State, w, h, pw, ph int) image.Rectangle {
+	win := state.WindowBounds()
+	spaceAbove := -win.Min.Y
+	spaceBelow := win.Max.Y - h
+	var r image.Rectangle
+	if ph <= spaceBelow {
+		r = draw.XYWH(0, h, pw, ph)
+	} else if ph <= spaceAbove {
+		r = draw.XYWH(0, -ph, pw, ph)
+	} else if spaceAbove > spaceBelow {
+		r = draw.XYWH(0, -spaceAbove, pw+15, spaceAbove)
+	} else {
+		r = draw.XYWH(0, h, pw+15, spaceBelow)
+	}
+	if r.Dx() < w {
+		r.Max.X = r.Min.X + w
+	}
+	return r
+}
@@ -21,6 +21,8 @@ type TabContainer struct {
 	Changed  func(*ui.State, int)
 	Theme    *Theme
 	close    tabCloseButton
+	menu     *Menu
+	menuTab  *MenuItem
 }
 
 func NewTabContainer() *TabContainer {
@@ -31,6 +33,9 @@ func NewTabContainer() *TabContainer {
 
 func (t *TabContainer) SetTheme(theme *Theme) {
 	t.Theme = theme
+	if t.menu != nil {
+		t.menu.SetTheme(theme)
+	}
 	for _, t := range t.Tabs {
 		SetTheme(t.Content, theme)
 	}
@@ -47,6 +52,55 @@ func (t *TabContainer) AddClosableTab(title string, content ui.Component, close
 	t.Tabs = append(t.Tabs, Tab{Title: title, Content: content, Close: close})
 }
 
+// ContextMenu returns the menu that is shown when a tab is right-clicked.
+// Applications may add their own items, the selected tab is the one that was clicked.
+func (t *TabContainer) ContextMenu() *Menu {
+	if t.menu == nil {
+		t.menu = NewPopupMenu("")
+		t.menu.SetTheme(t.Theme)
+		t.menuTab = t.menu.AddItem("&Close Tab", func(state *ui.State) {
+			if i := t.Selected; i >= 0 && i < len(t.Tabs) && t.Tabs[i].Close != nil {
+				t.Tabs[i].Close(state, i)
+			}
+		})
+		t.menu.AddItem("Close &Other Tabs", t.closeOthers)
+	}
+	return t.menu
+}
+
+// closeOthers closes all closable tabs except the selected one.
+// A Close function may keep its tab open or change the other tabs, so the tabs are found by their content.
+func (t *TabContainer) closeOthers(state *ui.State) {
+	if t.Selected < 0 || t.Selected >= len(t.Tabs) {
+		return
+	}
+	keep := t.Tabs[t.Selected].Content
+	var close []ui.Component
+	for i, tab := range t.Tabs {
+		if i != t.Selected && tab.Close != nil {
+			close = append(close, tab.Content)
+		}
+	}
+	for j := len(close) - 1; j >= 0; j-- {
+		if i := t.indexOf(close[j]); i >= 0 && t.Tabs[i].Close != nil {
+			t.Tabs[i].Close(state, i)
+		}
+	}
+	if i := t.indexOf(keep); i >= 0 {
+		t.Selected = i
+	}
+}
+
+// indexOf returns the index of the tab showing content, or -1.
+func (t *TabContainer) indexOf(content ui.Component) int {
+	for i, tab := range t.Tabs {
+		if tab.Content == content {
+			return i
+		}
+	}
+	return -1
+}
+
 func (t *TabContainer) CloseTab(i int) {
 	if i < 0 || i >= len(t.Tabs) {
 		return
@@ -92,7 +146,7 @@ func (t *TabContainer) Update(g *draw.Buffer, state *ui.State) {
 	contentArea := draw.XYXY(10, hh+10, w-10, h-10)
 	x := 0
 	mouse := state.MousePos()
-	close := -1
+	close, menu := -1, -1
 	for i, tab := range t.Tabs {
 		hw, _ := tab.title.Size(tab.Title, t.Theme.Font("title"), g.FontLookup)
 		w := hw + 20
@@ -100,6 +154,9 @@ func (t *TabContainer) Update(g *draw.Buffer, state *ui.State) {
 			w += hh
 		}
 		rect := draw.XYWH(x+10, 10, w, hh)
+		if mouse.In(rect) && state.MouseButtonDown(ui.MouseRight) {
+			menu = i
+		}
 		if i != t.Selected && mouse.In(rect) && state.MouseClick(ui.MouseLeft) {
 			t.Selected = i
 			if t.Changed != nil {
@@ -127,7 +184,20 @@ func (t *TabContainer) Update(g *draw.Buffer, state *ui.State) {
 	}
 	g.Fill(contentArea, t.Theme.Color("background"))
 	state.UpdateChild(g, contentArea, t.Tabs[t.Selected].Content)
-	if close >= 0 {
+	if menu >= 0 {
+		// the menu is opened after all tabs are drawn, so the rest of the container does not disappear for a frame
+		if menu != t.Selected {
+			t.Selected = menu
+			if t.Changed != nil {
+				t.Changed(state, menu)
+			}
+		}
+		m := t.ContextMenu()
+		t.menuTab.Disabled = t.Tabs[menu].Close == nil
+		m.OpenPopupMenu(mouse, state, g.FontLookup)
+		state.InitiateDrag(ui.MenuDrag)
+		state.RequestUpdate()
+	} else if close >= 0 {
 		t.Tabs[close].Close(state, close)
 		state.RequestUpdate()
 	}
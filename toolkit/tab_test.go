@@ -0,0 +1,39 @@
+package toolkit
+
+import (
+	"testing"
+
+	"github.com/jfreymuth/ui"
+)
+
+func TestTabContainerCloseOthers(t *testing.T) {
+	var st ui.BackendState
+	tc := NewTabContainer()
+	contents := make([]*Label, 5)
+	for i := range contents {
+		contents[i] = NewLabel(string(rune('a' + i)))
+	}
+	tc.AddClosableTab("a", contents[0], nil)
+	// b refuses to be closed
+	tc.AddClosableTab("b", contents[1], func(*ui.State, int) {})
+	tc.AddTab("c", contents[2])
+	tc.AddClosableTab("d", contents[3], nil)
+	// closing e moves the last tab to the front
+	tc.AddClosableTab("e", contents[4], func(_ *ui.State, i int) {
+		tc.CloseTab(i)
+		last := tc.Tabs[len(tc.Tabs)-1]
+		tc.Tabs = append([]Tab{last}, tc.Tabs[:len(tc.Tabs)-1]...)
+	})
+	tc.Selected = 2
+	tc.closeOthers(&st.State)
+	var titles string
+	for _, tab := range tc.Tabs {
+		titles += tab.Title
+	}
+	if titles != "bc" {
+		t.Errorf("tabs %q, want %q", titles, "bc")
+	}
+	if tc.Tabs[tc.Selected].Content != contents[2] {
+		t.Errorf("selected %q", tc.Tabs[tc.Selected].Title)
+	}
+}
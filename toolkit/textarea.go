@@ -1,9 +1,10 @@
 package toolkit
 
 import (
-	"bufio"
 	"image"
 	"io"
+	"io/ioutil"
+	"sort"
 	"strings"
 	"unicode/utf8"
 
@@ -13,29 +14,40 @@ import (
 )
 
 type TextArea struct {
-	Editable       bool
-	Theme          *Theme
-	Font, font     draw.Font
-	text           []string
-	cx             int
-	w, h, b        int
-	cursor         cursor
-	selectionStart cursor
-	changedLine    int
-	ll, sll, slll  int
-	lastX, lastY   int
-	state          byte
-	scr            bool
-	changed        bool
-	popup          Menu
+	Editable bool
+	// TabWidth is the distance between tab stops, measured in spaces.
+	TabWidth int
+	// If InsertSpaces is true, the tab key will insert spaces instead of tab characters.
+	InsertSpaces bool
+	// If AutoIndent is true, new lines will be indented like the previous line.
+	AutoIndent   bool
+	Theme        *Theme
+	Font, font   draw.Font
+	text         pieceTable
+	tabWidth     int
+	tab          float32
+	w, h, b      int
+	widths       []int // width of every line, -1 if the line has not been measured yet
+	ll           int   // longest line
+	rescan       bool
+	sel          []selection
+	primary      int
+	rectStart    cursor
+	rectX        int
+	lastX, lastY int
+	state        byte
+	scr          bool
+	changed      bool
+	popup        Menu
+	copied       []string // the text of each selection when the text was last copied
 }
 
 func NewTextArea() *TextArea {
-	t := &TextArea{Theme: DefaultTheme, Font: DefaultTheme.Font("inputText"), h: -1, text: []string{""}, Editable: true}
+	t := &TextArea{Theme: DefaultTheme, Font: DefaultTheme.Font("inputText"), h: -1, sel: []selection{{cx: -1}}, Editable: true, TabWidth: 4, AutoIndent: true}
 	t.popup = *NewPopupMenu("")
-	t.popup.AddItem("Cut", t.Cut)
-	t.popup.AddItem("Copy", t.Copy)
-	t.popup.AddItem("Paste", t.Paste)
+	t.popup.AddItem("Cu&t", t.Cut)
+	t.popup.AddItem("&Copy", t.Copy)
+	t.popup.AddItem("&Paste", t.Paste)
 	return t
 }
 
@@ -43,80 +55,123 @@ type cursor struct {
 	line, col int
 }
 
+func (c cursor) less(d cursor) bool {
+	return c.line < d.line || c.line == d.line && c.col < d.col
+}
+
+// A selection is a range of text with a cursor at one end.
+// Every selection has its own cursor, so there may be multiple cursors at the same time.
+type selection struct {
+	start, cursor cursor
+	cx            int // horizontal cursor position used when moving up and down, -1 if unknown
+}
+
+func (s selection) bounds() (cursor, cursor) {
+	if s.start.less(s.cursor) {
+		return s.start, s.cursor
+	}
+	return s.cursor, s.start
+}
+
+func (s selection) empty() bool { return s.start == s.cursor }
+
+// ContextMenu returns the menu that is shown when the text area is right-clicked.
+// Applications may add their own items.
+func (t *TextArea) ContextMenu() *Menu { return &t.popup }
+
 func (t *TextArea) SetTheme(theme *Theme) {
 	t.Theme = theme
 	t.popup.SetTheme(theme)
 }
-func (t *TextArea) Text() string { return strings.Join(t.text, "\n") }
+
+// Text returns the contents of the text area, with lines separated by "\n".
+// WriteTextTo and Reader keep the original line endings.
+func (t *TextArea) Text() string { return strings.Replace(t.text.String(), "\r\n", "\n", -1) }
 func (t *TextArea) SetText(text string) {
-	t.text = strings.Split(strings.Replace(text, "\t", "    ", -1), "\n")
+	t.text.reset(text, detectCRLF(text))
 	t.textReplaced()
 }
 
+// Lines returns the lines of the text area, without line breaks.
 func (t *TextArea) Lines() []string {
-	return append(make([]string, 0, len(t.text)), t.text...)
+	l := make([]string, t.text.lineCount())
+	for i := range l {
+		l[i] = t.text.line(i)
+	}
+	return l
 }
 
 func (t *TextArea) SetLines(l []string) {
-	if len(l) == 0 {
-		t.text = []string{""}
-	} else {
-		t.text = append(t.text[:0], l...)
-	}
+	t.text.reset(strings.Join(l, t.newline()), t.text.crlf)
 	t.textReplaced()
 }
 
 func (t *TextArea) Append(text string) {
-	t.text = append(t.text, strings.Split(strings.Replace(text, "\t", "    ", -1), "\n")...)
+	last := t.text.lineCount() - 1
+	text = strings.Replace(text, "\r\n", "\n", -1)
+	t.text.replace(t.text.size, t.text.size, t.newline()+t.lineBreaks(text))
+	t.linesChanged(last, last, strings.Count(text, "\n")+2)
 }
 
 func (t *TextArea) SetTextFromReader(r io.Reader) error {
-	t.text = nil
-	return t.AppendFromReader(r)
+	b, err := ioutil.ReadAll(r)
+	t.SetText(string(b))
+	return err
 }
 
 func (t *TextArea) AppendFromReader(r io.Reader) error {
-	sc := bufio.NewScanner(r)
-	for sc.Scan() {
-		t.text = append(t.text, strings.Replace(sc.Text(), "\t", "    ", -1))
-	}
-	t.text = append(t.text, strings.Replace(sc.Text(), "\t", "    ", -1))
+	b, err := ioutil.ReadAll(r)
+	t.Append(string(b))
 	t.textReplaced()
-	return sc.Err()
+	return err
 }
 
+// WriteTextTo writes the contents of the text area to w.
+// Line endings are written in the style that was detected when the text was set.
 func (t *TextArea) WriteTextTo(w io.Writer) error {
-	text := t.text
-	if t.text[len(t.text)-1] == "" {
-		text = t.text[:len(t.text)-1]
+	return t.text.writeTo(w)
+}
+
+// detectCRLF reports whether all lines in text are separated by "\r\n".
+func detectCRLF(text string) bool {
+	n := strings.Count(text, "\n")
+	return n > 0 && strings.Count(text, "\r\n") == n
+}
+
+// lineBreaks converts the line breaks in s to the style used by the text area.
+func (t *TextArea) lineBreaks(s string) string {
+	if t.text.crlf {
+		return strings.Replace(s, "\n", "\r\n", -1)
 	}
-	for _, l := range text {
-		_, err := io.WriteString(w, l)
-		if err != nil {
-			return err
-		}
-		_, err = io.WriteString(w, "\n")
-		if err != nil {
-			return err
-		}
+	return s
+}
+
+func (t *TextArea) newline() string {
+	if t.text.crlf {
+		return "\r\n"
 	}
-	return nil
+	return "\n"
 }
 
 func (t *TextArea) ReplaceSelection(text string) {
-	s1, _ := t.selection()
-	t.insert(text)
-	t.selectionStart = s1
+	t.replace(func(int) string { return text }, true)
 }
 
 func (t *TextArea) textReplaced() {
-	t.cursor = cursor{len(t.text) - 1, len(t.text[len(t.text)-1])}
-	t.selectionStart = t.cursor
+	end := t.end()
+	t.sel = append(t.sel[:0], selection{end, end, -1})
+	t.primary = 0
 	t.h = -1
-	t.cx = -1
 	t.changed = true
 }
 
+// CursorPosition returns the line and column of the primary cursor, both starting at 0.
+// The column is measured in characters.
+func (t *TextArea) CursorPosition() (line, column int) {
+	c := t.sel[t.primary].cursor
+	return c.line, utf8.RuneCountInString(t.text.line(c.line)[:c.col])
+}
+
 func (t *TextArea) Changed() bool {
 	c := t.changed
 	t.changed = false
@@ -125,11 +180,11 @@ func (t *TextArea) Changed() bool {
 
 func (t *TextArea) PreferredSize(fonts draw.FontLookup) (int, int) {
 	t.measure(nil, fonts)
-	w, h := t.w, t.h*len(t.text)
+	w, h := t.w, t.h*t.text.lineCount()
 	if w < 200 {
 		w = 200
 	}
-	if len(t.text) < 3 {
+	if t.text.lineCount() < 3 {
 		h = t.h * 3
 	}
 	return w + 4, h + 4
@@ -148,66 +203,81 @@ func (t *TextArea) Update(g *draw.Buffer, state *ui.State) {
 	if t.Editable && state.HasKeyboardFocus() {
 		g.Outline(draw.WH(w, h), t.Theme.Color("border"))
 	}
-	s1, s2 := t.selection()
-
-	x := 2
-	y := 2 + s1.line*t.h
-	cx, cy := -1, -1
-	x += int(m.Advance(t.text[s1.line][:s1.col]))
-	if state.HasKeyboardFocus() && s1 == t.cursor {
-		if t.cx < 0 {
-			t.cx = x
-			t.scr = true
+	color := t.Theme.Color("selectionInactive")
+	if state.HasKeyboardFocus() {
+		color = t.Theme.Color("selection")
+	}
+	clip := g.Clip()
+	first, last := (clip.Min.Y-2)/t.h, (clip.Max.Y-2)/t.h+1
+	if first < 0 {
+		first = 0
+	}
+	if n := t.text.lineCount(); last > n {
+		last = n
+	}
+	cx := make([]int, len(t.sel))
+	for i, s := range t.sel {
+		cx[i] = -1
+		if s1, s2 := s.bounds(); i == t.primary || s2.line >= first && s1.line < last {
+			cx[i] = t.drawSelection(g, state, m, i, w, color)
+		}
+	}
+	tw := t.w
+	for i := first; i < last; i++ {
+		t.lineWidth(m, i)
+		t.drawLine(g, m, 2+i*t.h+t.b, t.text.line(i), t.Theme.Color("inputText"))
+	}
+	if t.w != tw {
+		state.RequestUpdate()
+	}
+	if t.Editable && state.Blink() {
+		for i, s := range t.sel {
+			if cx[i] >= 0 {
+				g.Fill(draw.XYWH(cx[i]-1, 2+s.cursor.line*t.h, 2, t.h), t.Theme.Color("inputText"))
+			}
 		}
-		t.scroll(state, x)
-		cx, cy = x, y
 	}
+}
+
+// drawSelection draws the background of a selection and returns the horizontal position of its cursor.
+func (t *TextArea) drawSelection(g *draw.Buffer, state *ui.State, m draw.FontMetrics, i int, w int, color draw.Color) int {
+	s := &t.sel[i]
+	s1, s2 := s.bounds()
+	x1 := 2 + int(t.advance(m, t.text.line(s1.line)[:s1.col]))
+	x2 := 2 + int(t.advance(m, t.text.line(s2.line)[:s2.col]))
+	y1, y2 := 2+s1.line*t.h, 2+s2.line*t.h
 	if s1.line == s2.line {
-		adv := m.Advance(t.text[s1.line][s1.col:s2.col])
 		if s1.col != s2.col {
-			if state.HasKeyboardFocus() {
-				g.Fill(draw.XYWH(x, y, int(adv), t.h), t.Theme.Color("selection"))
-			} else {
-				g.Fill(draw.XYWH(x, y, int(adv), t.h), t.Theme.Color("selectionInactive"))
-			}
+			g.Fill(draw.XYXY(x1, y1, x2, y1+t.h), color)
 		}
-		x += int(adv)
 	} else {
-		var color draw.Color
-		if state.HasKeyboardFocus() {
-			color = t.Theme.Color("selection")
-		} else {
-			color = t.Theme.Color("selectionInactive")
-		}
-		g.Fill(draw.XYXY(x, y, w-2, y+t.h), color)
-		g.Fill(draw.XYWH(2, 2+(s1.line+1)*t.h, w-4, (s2.line-s1.line-1)*t.h), color)
-		x, y = 2, 2+s2.line*t.h
-		adv := m.Advance(t.text[s2.line][:s2.col])
-		g.Fill(draw.XYWH(x, y, int(adv), t.h), color)
-		x += int(adv)
-	}
-	if state.HasKeyboardFocus() && s2 == t.cursor {
-		if t.cx < 0 {
-			t.cx = int(x)
-			t.scr = true
-		}
-		t.scroll(state, int(x))
-		cx, cy = x, y
+		g.Fill(draw.XYXY(x1, y1, w-2, y1+t.h), color)
+		g.Fill(draw.XYXY(2, y1+t.h, w-2, y2), color)
+		g.Fill(draw.XYXY(2, y2, x2, y2+t.h), color)
 	}
-	for i := range t.text {
-		g.Text(image.Pt(2, 2+i*t.h+t.b), t.text[i], t.Theme.Color("inputText"), t.font)
+	x := x2
+	if s.cursor == s1 {
+		x = x1
 	}
-	if t.Editable && cx >= 0 && state.Blink() {
-		g.Fill(draw.XYWH(cx-1, cy, 2, t.h), t.Theme.Color("inputText"))
+	if state.HasKeyboardFocus() {
+		if s.cx < 0 {
+			s.cx = x
+			if i == t.primary {
+				t.scr = true
+			}
+		}
+		if i == t.primary {
+			t.scroll(state, x, s.cursor.line)
+		}
 	}
+	return x
 }
 
 func (t *TextArea) hanldeMouseEvents(state *ui.State, fonts draw.FontLookup) {
 	mouse := state.MousePos()
 	drag, drop := state.DraggedContent()
 	if drag, ok := drag.(string); ok {
-		t.cursor = t.getCursor(fonts, mouse)
-		t.selectionStart = t.cursor
+		t.setCursor(t.getCursor(fonts, mouse))
 		state.SetBlink()
 		if drop {
 			t.insert(drag)
@@ -226,24 +296,43 @@ func (t *TextArea) hanldeMouseEvents(state *ui.State, fonts draw.FontLookup) {
 				state.InitiateDrag(t.SelectedText())
 				t.insert("")
 			}
-		} else if t.state == tfIdle && state.ClickCount() == 1 && t.inSelection(c) {
+		} else if t.state == tfRect {
+			t.selectRect(fonts, c, mouse.X)
+		} else if t.state == tfIdle && state.HasModifiers(ui.Alt) {
+			t.rectStart, t.rectX = c, mouse.X
+			t.state = tfRect
+			t.selectRect(fonts, c, mouse.X)
+			state.SetBlink()
+		} else if t.state == tfIdle && state.ClickCount() == 1 && len(t.sel) == 1 && !state.HasModifiers(ui.Control) && t.inSelection(c) {
 			t.state = tfDrag
 		} else {
 			if t.lastX != mouse.X || t.lastY != mouse.Y {
-				t.cursor = c
-				t.cx = mouse.X
-				t.scr = true
 				if t.state == tfIdle {
 					t.state = tfSelect
-					t.selectionStart = t.cursor
+					if state.HasModifiers(ui.Control) {
+						t.addSelection(c)
+					} else {
+						t.setCursor(c)
+					}
 				}
+				p := &t.sel[t.primary]
+				p.cursor = c
+				p.cx = mouse.X
+				t.scr = true
+				t.normalize()
 			} else if t.state == tfIdle {
 				switch state.ClickCount() % 3 {
 				case 1:
-					t.selectionStart, t.cursor = c, c
+					if state.HasModifiers(ui.Control) {
+						t.addSelection(c)
+					} else {
+						t.setCursor(c)
+					}
 				case 2:
-					t.selectionStart.line, t.cursor.line = c.line, c.line
-					t.selectionStart.col, t.cursor.col = text.FindWord(t.text[c.line], c.col)
+					p := &t.sel[t.primary]
+					p.start.line, p.cursor.line = c.line, c.line
+					p.start.col, p.cursor.col = text.FindWord(t.text.line(c.line), c.col)
+					t.normalize()
 					t.state = tfSelect
 				case 0:
 					t.selectLine()
@@ -255,16 +344,15 @@ func (t *TextArea) hanldeMouseEvents(state *ui.State, fonts draw.FontLookup) {
 		}
 	} else {
 		if t.state == tfDrag {
-			t.cursor = t.getCursor(fonts, mouse)
-			t.selectionStart = t.cursor
+			t.setCursor(t.getCursor(fonts, mouse))
 			state.SetBlink()
 		}
 		t.state = tfIdle
 	}
 	if state.MouseButtonDown(ui.MouseRight) {
 		c := t.getCursor(fonts, mouse)
-		if !t.inSelection(c) && c != t.cursor && c != t.selectionStart {
-			t.cursor, t.selectionStart = c, c
+		if !t.inSelection(c) && !t.atCursor(c) {
+			t.setCursor(c)
 		}
 		t.popup.OpenPopupMenu(mouse, state, fonts)
 		state.InitiateDrag(ui.MenuDrag)
@@ -279,150 +367,261 @@ func (t *TextArea) handleKeyEvents(state *ui.State, fonts draw.FontLookup) {
 	for _, k := range state.KeyPresses() {
 		switch k {
 		case ui.KeyLeft:
-			t.cursor = t.prev(state)
-			t.cx = -1
+			for i := range t.sel {
+				t.sel[i].cursor = t.prev(state, t.sel[i].cursor)
+				t.sel[i].cx = -1
+			}
 		case ui.KeyRight:
-			t.cursor = t.next(state)
-			t.cx = -1
+			for i := range t.sel {
+				t.sel[i].cursor = t.next(state, t.sel[i].cursor)
+				t.sel[i].cx = -1
+			}
 		case ui.KeyUp:
-			if t.cursor.line > 0 {
-				t.cursor.line--
-				t.cursor.col = t.findPosition(fonts, t.cursor.line, t.cx)
-			} else {
-				t.cursor.col = 0
+			for i := range t.sel {
+				s := &t.sel[i]
+				if s.cx < 0 {
+					s.cx = t.xPosition(fonts, s.cursor)
+				}
+				if s.cursor.line > 0 {
+					s.cursor.line--
+					s.cursor.col = t.findPosition(fonts, s.cursor.line, s.cx)
+				} else {
+					s.cursor.col = 0
+				}
 			}
 			t.scr = true
 		case ui.KeyDown:
-			if t.cursor.line < len(t.text)-1 {
-				t.cursor.line++
-				t.cursor.col = t.findPosition(fonts, t.cursor.line, t.cx)
-			} else {
-				t.cursor.col = len(t.text[t.cursor.line])
+			for i := range t.sel {
+				s := &t.sel[i]
+				if s.cx < 0 {
+					s.cx = t.xPosition(fonts, s.cursor)
+				}
+				if s.cursor.line < t.text.lineCount()-1 {
+					s.cursor.line++
+					s.cursor.col = t.findPosition(fonts, s.cursor.line, s.cx)
+				} else {
+					s.cursor.col = len(t.text.line(s.cursor.line))
+				}
 			}
 			t.scr = true
 		case ui.KeyHome:
-			t.cursor.col = 0
-			t.cx = -1
+			for i := range t.sel {
+				t.sel[i].cursor.col = 0
+				t.sel[i].cx = -1
+			}
 		case ui.KeyEnd:
-			t.cursor.col = len(t.text[t.cursor.line])
-			t.cx = -1
+			for i := range t.sel {
+				t.sel[i].cursor.col = len(t.text.line(t.sel[i].cursor.line))
+				t.sel[i].cx = -1
+			}
 		case ui.KeyBackspace:
-			if t.Editable && t.cursor == t.selectionStart {
-				t.cursor = t.prev(state)
+			if t.Editable {
+				for i := range t.sel {
+					if t.sel[i].empty() {
+						t.sel[i].cursor = t.prev(state, t.sel[i].cursor)
+					}
+				}
 			}
 			t.insert("")
 		case ui.KeyDelete:
-			if t.Editable && t.cursor == t.selectionStart {
-				t.cursor = t.next(state)
+			if t.Editable {
+				for i := range t.sel {
+					if t.sel[i].empty() {
+						t.sel[i].cursor = t.next(state, t.sel[i].cursor)
+					}
+				}
 			}
 			t.insert("")
 		case ui.KeyEnter:
-			t.insert("\n")
+			t.newLine()
 		case ui.KeyTab:
-			t.insert("\t")
+			t.tabKey(state)
+		case ui.KeyEscape:
+			if len(t.sel) > 1 {
+				t.sel = append(t.sel[:0], t.sel[t.primary])
+				t.primary = 0
+				t.scr = true
+			} else {
+				state.IgnoreKeyPress(k)
+			}
+			continue
+		case ui.KeyD:
+			if state.HasModifiers(ui.Control) {
+				t.AddNextOccurrence(state)
+			} else {
+				state.IgnoreKeyPress(k)
+			}
+			continue
 		case ui.KeyMenu:
-			t.popup.OpenPopupMenu(image.Pt(t.cx, (t.cursor.line+1)*t.h), state, fonts)
+			p := t.sel[t.primary]
+			t.popup.OpenPopupMenu(image.Pt(p.cx, (p.cursor.line+1)*t.h), state, fonts)
 			continue
 		default:
+			state.IgnoreKeyPress(k)
 			continue
 		}
 		if !state.HasModifiers(ui.Shift) {
-			t.selectionStart = t.cursor
+			for i := range t.sel {
+				t.sel[i].start = t.sel[i].cursor
+			}
 		}
+		t.normalize()
 		state.SetBlink()
 	}
 }
 
 func (t *TextArea) SelectAll(state *ui.State) {
-	t.selectionStart = cursor{0, 0}
-	t.cursor = cursor{len(t.text) - 1, len(t.text[len(t.text)-1])}
+	t.sel = append(t.sel[:0], selection{cursor{0, 0}, t.end(), -1})
+	t.primary = 0
+}
+
+// AddNextOccurrence adds a selection for the next occurrence of the most recently selected text.
+// If that selection is empty, the word under its cursor will be selected instead.
+func (t *TextArea) AddNextOccurrence(state *ui.State) {
+	p := &t.sel[t.primary]
+	if p.empty() {
+		line := p.cursor.line
+		s1, s2 := text.FindWord(t.text.line(line), p.cursor.col)
+		p.start, p.cursor, p.cx = cursor{line, s1}, cursor{line, s2}, -1
+		t.normalize()
+		state.SetBlink()
+		return
+	}
+	s1, s2 := p.bounds()
+	needle := t.text.slice(t.offset(s1), t.offset(s2))
+	start := t.offset(s2)
+	wrapped := false
+	for off := start; ; {
+		i := t.text.index(needle, off)
+		if i < 0 {
+			if wrapped {
+				return
+			}
+			wrapped, off = true, 0
+			continue
+		}
+		if wrapped && i >= start {
+			return
+		}
+		c1, c2 := t.cursorAt(i), t.cursorAt(i+len(needle))
+		if !t.isSelected(c1, c2) {
+			t.sel = append(t.sel, selection{c1, c2, -1})
+			t.primary = len(t.sel) - 1
+			t.normalize()
+			state.SetBlink()
+			return
+		}
+		off = i + len(needle)
+	}
 }
 
 func (t *TextArea) Cut(state *ui.State) {
-	state.SetClipboardString(t.SelectedText())
+	t.Copy(state)
 	t.insert("")
 }
 
 func (t *TextArea) Copy(state *ui.State) {
-	state.SetClipboardString(t.SelectedText())
+	t.copied = t.copied[:0]
+	for _, s := range t.sel {
+		t.copied = append(t.copied, t.textRange(s.bounds()))
+	}
+	state.SetClipboardString(strings.Join(t.copied, "\n"))
 }
 
+// Paste inserts the contents of the clipboard at every cursor.
+// If there are multiple cursors, and the clipboard contains the text copied from as many selections
+// or one line per cursor, each cursor will receive one part.
 func (t *TextArea) Paste(state *ui.State) {
-	t.insert(state.ClipboardString())
+	s := strings.Replace(state.ClipboardString(), "\r\n", "\n", -1)
+	if len(t.sel) > 1 {
+		parts := t.copied
+		if len(parts) != len(t.sel) || strings.Join(parts, "\n") != s {
+			parts = strings.Split(s, "\n")
+		}
+		if len(parts) == len(t.sel) {
+			t.replace(func(i int) string { return parts[i] }, false)
+			return
+		}
+	}
+	t.insert(s)
 }
 
-func (t *TextArea) scroll(state *ui.State, x int) {
+func (t *TextArea) scroll(state *ui.State, x, line int) {
 	if t.scr {
-		state.RequestVisible(draw.XYWH(x+2-t.h*2, (t.cursor.line-1)*t.h+2, t.h*4, t.h*3))
+		state.RequestVisible(draw.XYWH(x+2-t.h*2, (line-1)*t.h+2, t.h*4, t.h*3))
 		t.scr = false
 	}
 }
 
+// eagerLines is the number of lines up to which the whole text is measured.
+// Larger texts are measured lazily, as lines become visible.
+const eagerLines = 10000
+
 func (t *TextArea) measure(state *ui.State, fonts draw.FontLookup) {
-	if t.h < 0 || t.Font != t.font {
+	if t.h < 0 || t.Font != t.font || t.TabWidth != t.tabWidth {
 		t.font = t.Font
+		t.tabWidth = t.TabWidth
 		m := fonts.Metrics(t.font)
 		t.h = m.LineHeight()
 		t.b = (t.h + m.Ascent() - m.Descent()) / 2
-		t.w = 200
-		t.slll = 0
-		for i, l := range t.text {
-			wf := int(m.Advance(l))
-			w := int(wf)
-			if w > t.w {
-				t.slll = t.w
-				t.sll = t.ll
-				t.w = w
-				t.ll = i
-			} else if w > t.slll {
-				t.slll = w
-				t.sll = i
-			}
+		t.tab = float32(t.tabWidth) * m.Advance(" ")
+		t.widths = t.widths[:0]
+		for i := t.text.lineCount(); i > 0; i-- {
+			t.widths = append(t.widths, -1)
 		}
-		t.changedLine = -1
+		t.rescan = true
 		if state != nil {
 			state.RequestUpdate()
 		}
-	} else if t.changedLine != -1 {
+	}
+	if t.rescan {
 		m := fonts.Metrics(t.font)
-		cll := int(m.Advance(t.text[t.changedLine]))
-		if t.changedLine == t.ll {
-			if cll >= t.slll {
-				t.w = cll
-			} else {
-				t.h = -1
+		eager := len(t.widths) <= eagerLines
+		t.w, t.ll = 200, 0
+		for i, w := range t.widths {
+			if w < 0 && eager {
+				w = t.lineWidth(m, i)
 			}
-		} else if t.changedLine == t.sll {
-			if cll < t.sll {
-				t.h = -1
-			} else if cll > t.w {
-				t.ll, t.sll = t.sll, t.ll
-				t.slll = t.w
-				t.w = cll
-			}
-		} else {
-			if cll > t.slll {
-				if cll > t.w {
-					t.slll = t.w
-					t.sll = t.ll
-					t.w = cll
-					t.ll = t.changedLine
-				} else {
-					t.slll = cll
-					t.sll = t.changedLine
-				}
+			if w > t.w {
+				t.w, t.ll = w, i
 			}
 		}
-		t.changedLine = -1
-		t.measure(state, fonts)
+		t.rescan = false
 	}
 }
 
-func (t *TextArea) lineChanged(l int) {
-	if t.changedLine == -1 {
-		t.changedLine = l
-	} else if t.changedLine != l {
-		t.h = -1
+// lineWidth returns the width of a line, measuring it if necessary.
+func (t *TextArea) lineWidth(m draw.FontMetrics, i int) int {
+	w := t.widths[i]
+	if w < 0 {
+		w = int(t.advance(m, t.text.line(i)))
+		t.widths[i] = w
+		if w > t.w {
+			t.w, t.ll = w, i
+		}
+	}
+	return w
+}
+
+// linesChanged updates the line widths after the lines from first to last have been replaced by n lines.
+func (t *TextArea) linesChanged(first, last, n int) {
+	if t.h < 0 {
+		return
+	}
+	old := len(t.widths)
+	d := n - (last - first + 1)
+	if d > 0 {
+		t.widths = append(t.widths, make([]int, d)...)
+	}
+	copy(t.widths[last+1+d:], t.widths[last+1:old])
+	t.widths = t.widths[:old+d]
+	for i := first; i < first+n; i++ {
+		t.widths[i] = -1
+	}
+	if t.ll >= first && t.ll <= last || len(t.widths) <= eagerLines {
+		t.rescan = true
+	} else if t.ll > last {
+		t.ll += d
 	}
 }
 
@@ -430,57 +629,220 @@ func (t *TextArea) getCursor(fonts draw.FontLookup, p image.Point) cursor {
 	line := (p.Y - 2) / t.h
 	if line < 0 {
 		return cursor{0, 0}
-	} else if line >= len(t.text) {
-		return cursor{len(t.text) - 1, len(t.text[len(t.text)-1])}
+	} else if line >= t.text.lineCount() {
+		return t.end()
 	}
 	col := t.findPosition(fonts, line, p.X)
 	return cursor{line, col}
 }
 
 func (t *TextArea) findPosition(fonts draw.FontLookup, line int, x int) int {
-	return fonts.Metrics(t.font).Index(t.text[line], float32(x-2))
+	return t.index(fonts.Metrics(t.font), t.text.line(line), float32(x-2))
 }
 
-func (t *TextArea) selection() (cursor, cursor) {
-	if t.selectionStart.line < t.cursor.line {
-		return t.selectionStart, t.cursor
+func (t *TextArea) xPosition(fonts draw.FontLookup, c cursor) int {
+	return 2 + int(t.advance(fonts.Metrics(t.font), t.text.line(c.line)[:c.col]))
+}
+
+// advance returns the width of s, taking tab stops into account.
+func (t *TextArea) advance(m draw.FontMetrics, s string) float32 {
+	x := float32(0)
+	for {
+		i := strings.IndexByte(s, '\t')
+		if i < 0 {
+			return x + m.Advance(s)
+		}
+		x = t.nextTabStop(x + m.Advance(s[:i]))
+		s = s[i+1:]
 	}
-	if t.selectionStart.line == t.cursor.line && t.selectionStart.col < t.cursor.col {
-		return t.selectionStart, t.cursor
+}
+
+// index returns the position in s that is closest to x, taking tab stops into account.
+func (t *TextArea) index(m draw.FontMetrics, s string, x float32) int {
+	pos, x0 := 0, float32(0)
+	for {
+		i := strings.IndexByte(s, '\t')
+		if i < 0 {
+			return pos + m.Index(s, x-x0)
+		}
+		end := x0 + m.Advance(s[:i])
+		if x < end {
+			return pos + m.Index(s[:i], x-x0)
+		}
+		next := t.nextTabStop(end)
+		if x < (end+next)/2 {
+			return pos + i
+		}
+		pos, x0, s = pos+i+1, next, s[i+1:]
+	}
+}
+
+func (t *TextArea) nextTabStop(x float32) float32 {
+	if t.tab <= 0 {
+		return x
+	}
+	return float32(int(x/t.tab)+1) * t.tab
+}
+
+func (t *TextArea) drawLine(g *draw.Buffer, m draw.FontMetrics, y int, s string, color draw.Color) {
+	x := float32(0)
+	for {
+		i := strings.IndexByte(s, '\t')
+		seg := s
+		if i >= 0 {
+			seg = s[:i]
+		}
+		if seg != "" {
+			g.Text(image.Pt(2+int(x), y), seg, color, t.font)
+		}
+		if i < 0 {
+			return
+		}
+		x = t.nextTabStop(x + m.Advance(seg))
+		s = s[i+1:]
 	}
-	return t.cursor, t.selectionStart
+}
+
+// normalize sorts the selections and merges selections that overlap.
+func (t *TextArea) normalize() {
+	p := t.sel[t.primary].cursor
+	sort.Slice(t.sel, func(i, j int) bool {
+		a, _ := t.sel[i].bounds()
+		b, _ := t.sel[j].bounds()
+		return a.less(b)
+	})
+	sel := t.sel[:1]
+	for _, s := range t.sel[1:] {
+		last := &sel[len(sel)-1]
+		l1, l2 := last.bounds()
+		s1, s2 := s.bounds()
+		if s1.less(l2) || s1 == l2 && (s.empty() || last.empty()) {
+			if l2.less(s2) {
+				l2 = s2
+			}
+			if last.cursor.less(last.start) {
+				last.start, last.cursor = l2, l1
+			} else {
+				last.start, last.cursor = l1, l2
+			}
+			continue
+		}
+		sel = append(sel, s)
+	}
+	t.sel = sel
+	t.primary = -1
+	for i, s := range t.sel {
+		if s.cursor == p {
+			t.primary = i
+			return
+		}
+	}
+	for i, s := range t.sel {
+		if s1, s2 := s.bounds(); !p.less(s1) && !s2.less(p) {
+			t.primary = i
+			return
+		}
+	}
+	t.primary = len(t.sel) - 1
+}
+
+// setCursor removes all selections and places a single cursor.
+func (t *TextArea) setCursor(c cursor) {
+	t.sel = append(t.sel[:0], selection{c, c, -1})
+	t.primary = 0
+}
+
+// addSelection adds a cursor without removing the existing selections.
+func (t *TextArea) addSelection(c cursor) {
+	t.sel = append(t.sel, selection{c, c, -1})
+	t.primary = len(t.sel) - 1
+	t.normalize()
+}
+
+// selectRect creates a rectangular selection, with one selection per line.
+func (t *TextArea) selectRect(fonts draw.FontLookup, c cursor, x int) {
+	step := 1
+	if c.line < t.rectStart.line {
+		step = -1
+	}
+	t.sel = t.sel[:0]
+	for l := t.rectStart.line; ; l += step {
+		t.sel = append(t.sel, selection{cursor{l, t.findPosition(fonts, l, t.rectX)}, cursor{l, t.findPosition(fonts, l, x)}, x})
+		if l == c.line {
+			break
+		}
+	}
+	t.primary = len(t.sel) - 1
+	t.normalize()
+	t.scr = true
 }
 
 func (t *TextArea) inSelection(c cursor) bool {
-	s1, s2 := t.selection()
-	if c.line < s1.line {
-		return false
-	} else if c.line == s1.line && c.col <= s1.col {
-		return false
-	} else if c.line > s2.line {
-		return false
-	} else if c.line == s2.line && c.col >= s2.col {
-		return false
+	for _, s := range t.sel {
+		if s1, s2 := s.bounds(); s1.less(c) && c.less(s2) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *TextArea) atCursor(c cursor) bool {
+	for _, s := range t.sel {
+		if s.start == c || s.cursor == c {
+			return true
+		}
 	}
-	return true
+	return false
 }
 
+func (t *TextArea) isSelected(c1, c2 cursor) bool {
+	for _, s := range t.sel {
+		if s1, s2 := s.bounds(); s1 == c1 && s2 == c2 {
+			return true
+		}
+	}
+	return false
+}
+
+// SelectedText returns the selected text.
+// If there are multiple selections, they will be separated by newlines.
 func (t *TextArea) SelectedText() string {
-	s1, s2 := t.selection()
-	if s1.line == s2.line {
-		return t.text[s1.line][s1.col:s2.col]
+	text := make([]string, len(t.sel))
+	for i, s := range t.sel {
+		text[i] = t.textRange(s.bounds())
 	}
-	if s1.line+1 == s2.line {
-		return t.text[s1.line][s1.col:] + "\n" + t.text[s2.line][:s2.col]
+	return strings.Join(text, "\n")
+}
+
+func (t *TextArea) textRange(s1, s2 cursor) string {
+	return strings.Replace(t.text.slice(t.offset(s1), t.offset(s2)), "\r\n", "\n", -1)
+}
+
+// offset converts a cursor to a byte offset into Text().
+func (t *TextArea) offset(c cursor) int {
+	return t.text.lineStart(c.line) + c.col
+}
+
+// cursorAt converts a byte offset into Text() to a cursor.
+func (t *TextArea) cursorAt(off int) cursor {
+	line := t.text.lineAt(off)
+	col := off - t.text.lineStart(line)
+	if l := len(t.text.line(line)); col > l {
+		col = l
 	}
-	return t.text[s1.line][s1.col:] + "\n" + strings.Join(t.text[s1.line+1:s2.line], "\n") + "\n" + t.text[s2.line][:s2.col]
+	return cursor{line, col}
+}
+
+// end returns the position after the last character.
+func (t *TextArea) end() cursor {
+	last := t.text.lineCount() - 1
+	return cursor{last, len(t.text.line(last))}
 }
 
-func (t *TextArea) next(state *ui.State) cursor {
-	c := t.cursor
-	line := t.text[c.line]
+func (t *TextArea) next(state *ui.State, c cursor) cursor {
+	line := t.text.line(c.line)
 	if c.col == len(line) {
-		if c.line == len(t.text)-1 {
+		if c.line == t.text.lineCount()-1 {
 			return c
 		}
 		return cursor{c.line + 1, 0}
@@ -488,94 +850,212 @@ func (t *TextArea) next(state *ui.State) cursor {
 	if state.HasModifiers(ui.Control) {
 		return cursor{c.line, text.NextWord(line, c.col)}
 	} else {
-		_, size := utf8.DecodeRuneInString(t.text[c.line][c.col:])
+		_, size := utf8.DecodeRuneInString(line[c.col:])
 		return cursor{c.line, c.col + size}
 	}
 }
 
-func (t *TextArea) prev(state *ui.State) cursor {
-	c := t.cursor
-	line := t.text[c.line]
+func (t *TextArea) prev(state *ui.State, c cursor) cursor {
+	line := t.text.line(c.line)
 	if c.col == 0 {
 		if c.line == 0 {
 			return c
 		}
-		return cursor{c.line - 1, len(t.text[c.line-1])}
+		return cursor{c.line - 1, len(t.text.line(c.line - 1))}
 	}
 	if state.HasModifiers(ui.Control) {
 		return cursor{c.line, text.PreviousWord(line, c.col)}
 	} else {
-		_, size := utf8.DecodeLastRuneInString(t.text[c.line][:c.col])
+		_, size := utf8.DecodeLastRuneInString(line[:c.col])
 		return cursor{c.line, c.col - size}
 	}
 }
 
 func (t *TextArea) selectLine() {
-	t.selectionStart.col = 0
-	if t.cursor.line == len(t.text)-1 {
-		t.cursor.col = len(t.text[t.cursor.line])
+	p := &t.sel[t.primary]
+	p.start.col = 0
+	if p.cursor.line == t.text.lineCount()-1 {
+		p.cursor.col = len(t.text.line(p.cursor.line))
 	} else {
-		t.cursor.col = 0
-		t.cursor.line++
+		p.cursor.col = 0
+		p.cursor.line++
 	}
+	t.normalize()
 }
 
 func (t *TextArea) insert(s string) {
-	if !t.Editable {
+	t.replace(func(int) string { return s }, false)
+}
+
+// newLine inserts a line break at every cursor.
+// If AutoIndent is set, the new line will start with the same whitespace as the line it was split from.
+func (t *TextArea) newLine() {
+	t.replace(func(i int) string {
+		if !t.AutoIndent {
+			return "\n"
+		}
+		s1, _ := t.sel[i].bounds()
+		line := t.text.line(s1.line)[:s1.col]
+		return "\n" + line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+	}, false)
+}
+
+// tabKey inserts a tab at every cursor, or changes the indentation if the selection spans multiple lines or Shift is held.
+func (t *TextArea) tabKey(state *ui.State) {
+	outdent := state.HasModifiers(ui.Shift)
+	for _, s := range t.sel {
+		if s1, s2 := s.bounds(); s1.line != s2.line {
+			t.indent(outdent)
+			return
+		}
+	}
+	if outdent {
+		t.indent(true)
 		return
 	}
-	s = strings.Replace(s, "\t", "    ", -1)
-	s1, s2 := t.selection()
-	if s == "" && s1 == s2 {
+	t.replace(func(i int) string {
+		if !t.InsertSpaces || t.TabWidth <= 0 {
+			return "\t"
+		}
+		s1, _ := t.sel[i].bounds()
+		col := 0
+		for _, r := range t.text.line(s1.line)[:s1.col] {
+			if r == '\t' {
+				col += t.TabWidth - col%t.TabWidth
+			} else {
+				col++
+			}
+		}
+		return strings.Repeat(" ", t.TabWidth-col%t.TabWidth)
+	}, false)
+}
+
+// indent adds one level of indentation to every selected line, or removes one if outdent is true.
+func (t *TextArea) indent(outdent bool) {
+	if !t.Editable {
 		return
 	}
-	if strings.Contains(s, "\n") {
-		lines := strings.Split(s, "\n")
-		ll := len(lines) - 1
-		t.cursor.col = len(lines[ll])
-		lines[ll] = lines[ll] + t.text[s2.line][s2.col:]
-		t.text[s1.line] = t.text[s1.line][:s1.col] + lines[0]
-		t.text = append(t.text[:s1.line+1], append(lines[1:], t.text[s2.line+1:]...)...)
-		t.cursor.line = s1.line + len(lines) - 1
-		t.h = -1
-	} else {
-		t.text[s1.line] = t.text[s1.line][:s1.col] + s + t.text[s2.line][s2.col:]
-		t.text = append(t.text[:s1.line+1], t.text[s2.line+1:]...)
-		t.cursor = cursor{s1.line, s1.col + len(s)}
-		if s1.line == s2.line {
-			t.lineChanged(s1.line)
+	unit := "\t"
+	if t.InsertSpaces && t.TabWidth > 0 {
+		unit = strings.Repeat(" ", t.TabWidth)
+	}
+	delta := make(map[int]int)
+	var lines []int
+	for _, s := range t.sel {
+		s1, s2 := s.bounds()
+		last := s2.line
+		if s2.col == 0 && s2.line > s1.line {
+			last--
+		}
+		for l := s1.line; l <= last; l++ {
+			if _, ok := delta[l]; !ok {
+				delta[l] = 0
+				lines = append(lines, l)
+			}
+		}
+	}
+	sort.Ints(lines)
+	// all lines are changed at once, the piece table only has to be rebuilt once
+	edits := make([]edit, 0, len(lines))
+	for _, l := range lines {
+		start := t.text.lineStart(l)
+		if outdent {
+			line := t.text.line(l)
+			n := 0
+			if strings.HasPrefix(line, "\t") {
+				n = 1
+			} else {
+				for n < len(line) && n < t.TabWidth && line[n] == ' ' {
+					n++
+				}
+			}
+			if n > 0 {
+				edits = append(edits, edit{start, start + n, ""})
+				delta[l] = -n
+			}
 		} else {
-			t.h = -1
+			edits = append(edits, edit{start, start, unit})
+			delta[l] = len(unit)
 		}
 	}
-	t.changed = true
-	t.selectionStart = t.cursor
-	t.cx = -1
+	if len(edits) > 0 {
+		t.text.replaceAll(edits)
+		for _, l := range lines {
+			if delta[l] != 0 {
+				t.linesChanged(l, l, 1)
+			}
+		}
+		t.changed = true
+	}
+	move := func(c cursor) cursor {
+		if d := delta[c.line]; d > 0 || c.col > 0 {
+			c.col += d
+			if c.col < 0 {
+				c.col = 0
+			}
+		}
+		return c
+	}
+	for i := range t.sel {
+		t.sel[i].start = move(t.sel[i].start)
+		t.sel[i].cursor = move(t.sel[i].cursor)
+		t.sel[i].cx = -1
+	}
+	t.normalize()
 }
 
-// Reader returns an io.Reader that will read from the contents of the text area.
-// Changes to the text area's content after this method is called will not affect the returned Reader.
-func (t *TextArea) Reader() io.Reader {
-	return &reader{t.Lines(), 0}
+// replace replaces the text of every selection with the string returned by f.
+// If keep is true, the inserted text will be selected,
+// otherwise the selections will be replaced by cursors placed after the inserted text.
+func (t *TextArea) replace(f func(i int) string, keep bool) {
+	if !t.Editable {
+		return
+	}
+	t.normalize()
+	for i := len(t.sel) - 1; i >= 0; i-- {
+		s1, s2 := t.sel[i].bounds()
+		s := f(i)
+		if s == "" && s1 == s2 {
+			continue
+		}
+		end := t.replaceRange(s1, s2, s)
+		for j := i + 1; j < len(t.sel); j++ {
+			t.sel[j].start = shift(t.sel[j].start, s2, end)
+			t.sel[j].cursor = shift(t.sel[j].cursor, s2, end)
+		}
+		if keep {
+			t.sel[i] = selection{s1, end, -1}
+		} else {
+			t.sel[i] = selection{end, end, -1}
+		}
+		t.changed = true
+	}
+	t.normalize()
 }
 
-type reader struct {
-	lines []string
-	pos   int
+// replaceRange replaces the text between s1 and s2 and returns the end of the inserted text.
+func (t *TextArea) replaceRange(s1, s2 cursor, s string) cursor {
+	s = strings.Replace(s, "\r\n", "\n", -1)
+	n := strings.Count(s, "\n")
+	end := cursor{s1.line + n, len(s) - strings.LastIndexByte(s, '\n') - 1}
+	if n == 0 {
+		end.col += s1.col
+	}
+	t.text.replace(t.offset(s1), t.offset(s2), t.lineBreaks(s))
+	t.linesChanged(s1.line, s2.line, n+1)
+	return end
 }
 
-func (r *reader) Read(p []byte) (int, error) {
-	if len(r.lines) == 0 {
-		return 0, io.EOF
+// shift moves a cursor located after old, so that it will be at the same position relative to new.
+func shift(c, old, new cursor) cursor {
+	if c.line == old.line {
+		return cursor{new.line, new.col + c.col - old.col}
 	}
-	n := copy(p, r.lines[0][r.pos:])
-	r.pos += n
-	p = p[n:]
-	if len(p) > 0 && r.pos == len(r.lines[0]) {
-		r.lines = r.lines[1:]
-		r.pos = 0
-		p[0] = '\n'
-		n++
-	}
-	return n, nil
+	return cursor{c.line + new.line - old.line, c.col}
+}
+
+// Reader returns an io.Reader that will read from the contents of the text area.
+// Changes to the text area's content after this method is called will not affect the returned Reader.
+func (t *TextArea) Reader() io.Reader {
+	return t.text.reader()
 }
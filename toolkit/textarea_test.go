@@ -0,0 +1,61 @@
+package toolkit
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/jfreymuth/ui"
+)
+
+func TestTextAreaCopyPaste(t *testing.T) {
+	var st ui.BackendState
+	ta := NewTextArea()
+	ta.SetText("one\ntwo\nthree\nfour\n")
+	// two selections, "ne\ntw" and "hree\nfo"
+	ta.sel = []selection{
+		{start: cursor{0, 1}, cursor: cursor{1, 2}, cx: -1},
+		{start: cursor{2, 1}, cursor: cursor{3, 2}, cx: -1},
+	}
+	ta.Copy(&st.State)
+	if got := st.ClipboardString(); got != "ne\ntw\nhree\nfo" {
+		t.Errorf("copied %q", got)
+	}
+	end := ta.end()
+	ta.sel = []selection{{start: cursor{0, 0}, cursor: cursor{0, 0}, cx: -1}, {start: end, cursor: end, cx: -1}}
+	ta.Paste(&st.State)
+	if got := ta.Text(); got != "ne\ntwone\ntwo\nthree\nfour\nhree\nfo" {
+		t.Errorf("pasted %q", got)
+	}
+
+	// text copied elsewhere is split into lines, regardless of the line breaks
+	ta.SetText("a\nb\n")
+	ta.sel = []selection{{cursor{0, 1}, cursor{0, 1}, -1}, {cursor{1, 1}, cursor{1, 1}, -1}}
+	st.SetClipboardString("1\r\n2")
+	ta.Paste(&st.State)
+	if got := ta.Text(); got != "a1\nb2\n" {
+		t.Errorf("pasted %q", got)
+	}
+	ta.sel = ta.sel[:1]
+	ta.Paste(&st.State)
+	if got := ta.Text(); got != "a11\n2\nb2\n" {
+		t.Errorf("pasted %q", got)
+	}
+}
+
+func TestTextAreaLineBreaks(t *testing.T) {
+	for _, text := range []string{"a\r\nb\r\nc", "a\r\nb\nc"} {
+		ta := NewTextArea()
+		ta.SetText(text)
+		if got := ta.Text(); got != "a\nb\nc" {
+			t.Errorf("Text() = %q", got)
+		}
+		if got := ta.Lines(); !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+			t.Errorf("Lines() = %q", got)
+		}
+		var buf bytes.Buffer
+		if err := ta.WriteTextTo(&buf); err != nil || buf.String() != text {
+			t.Errorf("WriteTextTo wrote %q, %v", buf.String(), err)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package toolkit
 
 import (
+	"image"
+	"strings"
 	"unicode/utf8"
 
 	"github.com/jfreymuth/ui"
@@ -9,12 +11,35 @@ import (
 )
 
 type TextField struct {
-	Editable       bool
-	Action         func(*ui.State, string)
-	MinWidth       int
-	Theme          *Theme
-	Text           string
+	Editable bool
+	Action   func(*ui.State, string)
+	MinWidth int
+	Theme    *Theme
+	Text     string
+	// Placeholder is shown in place of the text while the text is empty.
+	Placeholder string
+	// If Password is true, the text will be masked, and can not be copied or cut.
+	Password bool
+	// MaxLength is the maximum number of characters, or 0 for no limit.
+	MaxLength int
+	// Filter is called with typed, pasted or dropped text, and returns the text that will actually be inserted.
+	Filter func(string) string
+	// Validate is called when the text changes.
+	// If it returns an error, the text field will be highlighted and the error message will be shown.
+	Validate func(string) error
+	// Suggest is called when the text is edited, with the text before the cursor.
+	// The returned items are shown in a popup, choosing one replaces the text before the cursor.
+	Suggest        func(string) []ListItem
+	suggest        suggestions
+	menu           *Menu
+	menuItems      [3]*MenuItem
+	edited         bool
 	text           text.Text
+	hint           text.Text
+	msg            text.Text
+	err            error
+	validated      string
+	checked        bool
 	cursor         int
 	selectionStart int
 	lastX          int
@@ -26,45 +51,124 @@ func NewTextField() *TextField {
 	return &TextField{Theme: DefaultTheme, Editable: true, MinWidth: 100}
 }
 
-func (t *TextField) SetTheme(theme *Theme) { t.Theme = theme }
+func (t *TextField) SetTheme(theme *Theme) {
+	t.Theme = theme
+	if t.menu != nil {
+		t.menu.SetTheme(theme)
+	}
+}
+
+// ContextMenu returns the menu that is shown when the text field is right-clicked.
+// Applications may add their own items.
+func (t *TextField) ContextMenu() *Menu {
+	if t.menu == nil {
+		t.menu = NewPopupMenu("")
+		t.menu.SetTheme(t.Theme)
+		t.menuItems[0] = t.menu.AddItem("Cu&t", t.Cut)
+		t.menuItems[1] = t.menu.AddItem("&Copy", t.Copy)
+		t.menuItems[2] = t.menu.AddItem("&Paste", t.Paste)
+		t.menu.AddSeparator()
+		t.menu.AddItem("Select &All", t.SelectAll)
+	}
+	return t.menu
+}
+
+func (t *TextField) openMenu(p image.Point, state *ui.State, fonts draw.FontLookup) {
+	m := t.ContextMenu()
+	s1, s2 := t.selection()
+	t.menuItems[0].Disabled = s1 == s2 || t.Password || !t.Editable
+	t.menuItems[1].Disabled = s1 == s2 || t.Password
+	t.menuItems[2].Disabled = !t.Editable
+	m.OpenPopupMenu(p, state, fonts)
+}
 
 func (t *TextField) SelectedText() string {
 	s1, s2 := t.selection()
 	return t.Text[s1:s2]
 }
 
+// Error returns the error returned by Validate for the current text.
+func (t *TextField) Error() error {
+	t.validate()
+	return t.err
+}
+
+func (t *TextField) validate() {
+	if t.Validate == nil {
+		t.err = nil
+	} else if !t.checked || t.validated != t.Text {
+		t.err = t.Validate(t.Text)
+		t.validated, t.checked = t.Text, true
+	}
+}
+
 func (t *TextField) PreferredSize(fonts draw.FontLookup) (int, int) {
-	w, h := t.text.Size(t.Text, t.Theme.Font("inputText"), fonts)
+	font := t.Theme.Font("inputText")
+	w, h := t.text.Size(t.display(), font, fonts)
+	if t.Placeholder != "" {
+		if pw, _ := t.hint.Size(t.Placeholder, font, fonts); pw > w {
+			w = pw
+		}
+	}
 	if w+6 < t.MinWidth {
 		w = t.MinWidth - 6
 	}
+	t.validate()
+	if t.err != nil {
+		mw, mh := t.msg.Size(t.err.Error(), font, fonts)
+		if mw > w {
+			w = mw
+		}
+		return w + 6, h + mh + 6
+	}
 	return w + 6, h + 6
 }
 
 func (t *TextField) Update(g *draw.Buffer, state *ui.State) {
-	m := g.FontLookup.Metrics(t.Theme.Font("inputText"))
+	font := t.Theme.Font("inputText")
+	m := g.FontLookup.Metrics(font)
 	state.SetCursor(ui.CursorText)
-	t.handleKeyEvents(state)
-	t.handleMouseEvents(state, m)
+	if t.suggest.isOpen() {
+		state.DisableTabFocus()
+	}
+	menu := t.handleKeyEvents(state)
+	t.handleMouseEvents(state, m, g.FontLookup)
+	if !state.HasKeyboardFocus() || state.MouseClick(ui.MouseLeft) {
+		t.suggest.close()
+	} else if t.edited && t.Suggest != nil {
+		t.suggest.show(g, state, t, t.Suggest(t.Text[:t.cursor]), t.Theme, t.acceptSuggestion)
+	}
+	t.edited = false
 	w, h := g.Size()
-	_, th := t.text.Size(t.Text, t.Theme.Font("inputText"), g.FontLookup)
+	d := t.display()
+	_, th := t.text.Size(d, font, g.FontLookup)
 
+	t.validate()
+	lineColor := t.Theme.Color("inputText")
+	if t.err != nil {
+		h -= th
+		lineColor = t.Theme.Color("inputError")
+		t.msg.DrawLeft(g, draw.XYWH(3, h, w-3, th), t.err.Error(), font, lineColor)
+	}
 	animate(state, &t.anim, 8, t.Editable && state.HasKeyboardFocus())
 	anim := int(t.anim * float32(w))
-	g.Fill(draw.XYXY(0, (h-th)/2-3, anim, (h+th)/2+3), t.Theme.Color("inputBackground"))
+	g.RoundedFill(draw.XYXY(0, (h-th)/2-3, anim, (h+th)/2+3), t.Theme.Size("cornerRadius"), t.Theme.Color("inputBackground"))
 	line := w - 6 - anim
 	if line > 0 {
-		g.Fill(draw.XYWH(3, (h+th)/2, line, 1), t.Theme.Color("inputText"))
+		g.Fill(draw.XYWH(3, (h+th)/2, line, 1), lineColor)
 	}
 	x, y := float32(3), (h-th)/2
+	if d == "" && t.Placeholder != "" {
+		t.hint.DrawLeft(g, draw.XYWH(3, y, w-6, th), t.Placeholder, font, t.Theme.Color("placeholder"))
+	}
 	s1, s2 := t.selection()
-	x += m.Advance(t.Text[:s1])
+	x += m.Advance(d[:t.toDisplay(s1)])
 	var cx int
 	if s1 == t.cursor {
 		cx = int(x)
 	}
 	if s1 != s2 {
-		adv := m.Advance(t.Text[s1:s2])
+		adv := m.Advance(d[t.toDisplay(s1):t.toDisplay(s2)])
 		if state.HasKeyboardFocus() {
 			g.Fill(draw.XYWH(int(x), y, int(adv), th), t.Theme.Color("selection"))
 		} else {
@@ -78,14 +182,49 @@ func (t *TextField) Update(g *draw.Buffer, state *ui.State) {
 	if state.Blink() {
 		g.Fill(draw.XYWH(cx-1, y, 2, th), t.Theme.Color("inputText"))
 	}
-	t.text.DrawLeft(g, draw.XYXY(3, y, int(x), y+th), t.Text, t.Theme.Font("inputText"), t.Theme.Color("inputText"))
+	if menu {
+		t.openMenu(image.Pt(cx, y+th), state, g.FontLookup)
+	}
+	t.text.DrawLeft(g, draw.XYXY(3, y, int(x), y+th), d, font, t.Theme.Color("inputText"))
+}
+
+const passwordMask = "\u2022"
+
+// display returns the text as it is shown, with every character masked if Password is set.
+func (t *TextField) display() string {
+	if t.Password {
+		return strings.Repeat(passwordMask, utf8.RuneCountInString(t.Text))
+	}
+	return t.Text
+}
+
+// toDisplay converts an index into Text to an index into the displayed text.
+func (t *TextField) toDisplay(i int) int {
+	if t.Password {
+		return utf8.RuneCountInString(t.Text[:i]) * len(passwordMask)
+	}
+	return i
 }
 
-func (t *TextField) handleMouseEvents(state *ui.State, m draw.FontMetrics) {
+// index returns the position in Text that is closest to the horizontal position x.
+func (t *TextField) index(m draw.FontMetrics, x int) int {
+	if t.Password {
+		n := m.Index(t.display(), float32(x-3)) / len(passwordMask)
+		i := 0
+		for ; n > 0 && i < len(t.Text); n-- {
+			_, size := utf8.DecodeRuneInString(t.Text[i:])
+			i += size
+		}
+		return i
+	}
+	return m.Index(t.Text, float32(x-3))
+}
+
+func (t *TextField) handleMouseEvents(state *ui.State, m draw.FontMetrics, fonts draw.FontLookup) {
 	mx := state.MousePos().X
 	drag, drop := state.DraggedContent()
 	if drag, ok := drag.(string); ok {
-		t.cursor = m.Index(t.Text, float32(mx-3))
+		t.cursor = t.index(m, mx)
 		t.selectionStart = t.cursor
 		state.SetBlink()
 		if drop {
@@ -99,13 +238,13 @@ func (t *TextField) handleMouseEvents(state *ui.State, m draw.FontMetrics) {
 		return
 	}
 	if state.MouseButtonDown(ui.MouseLeft) {
-		c := m.Index(t.Text, float32(mx-3))
+		c := t.index(m, mx)
 		if t.state == tfDrag {
 			if !t.inSelection(c) {
 				state.InitiateDrag(t.SelectedText())
 				t.insert("")
 			}
-		} else if t.state == tfIdle && state.ClickCount() == 1 && t.inSelection(c) {
+		} else if t.state == tfIdle && state.ClickCount() == 1 && !t.Password && t.inSelection(c) {
 			t.state = tfDrag
 		} else {
 			if t.lastX != mx {
@@ -121,7 +260,11 @@ func (t *TextField) handleMouseEvents(state *ui.State, m draw.FontMetrics) {
 				case 1:
 					t.selectionStart, t.cursor = c, c
 				case 2:
-					t.selectionStart, t.cursor = text.FindWord(t.Text, t.cursor)
+					if t.Password {
+						t.SelectAll(state)
+					} else {
+						t.selectionStart, t.cursor = text.FindWord(t.Text, t.cursor)
+					}
 					t.state = tfSelect
 				case 0:
 					t.SelectAll(state)
@@ -133,12 +276,19 @@ func (t *TextField) handleMouseEvents(state *ui.State, m draw.FontMetrics) {
 		}
 	} else {
 		if t.state == tfDrag {
-			t.cursor = m.Index(t.Text, float32(mx-3))
+			t.cursor = t.index(m, mx)
 			t.selectionStart = t.cursor
 			state.SetBlink()
 		}
 		t.state = tfIdle
 	}
+	if state.MouseButtonDown(ui.MouseRight) {
+		if c := t.index(m, mx); !t.inSelection(c) {
+			t.cursor, t.selectionStart = c, c
+		}
+		t.openMenu(state.MousePos(), state, fonts)
+		state.InitiateDrag(ui.MenuDrag)
+	}
 }
 
 func (t *TextField) inSelection(c int) bool {
@@ -146,12 +296,28 @@ func (t *TextField) inSelection(c int) bool {
 	return c > s1 && c < s2
 }
 
-func (t *TextField) handleKeyEvents(state *ui.State) {
+func (t *TextField) handleKeyEvents(state *ui.State) (menu bool) {
 	if text := state.TextInput(); text != "" {
 		t.insert(text)
 		state.SetBlink()
 	}
 	for _, k := range state.KeyPresses() {
+		if t.suggest.isOpen() {
+			switch k {
+			case ui.KeyUp:
+				t.suggest.move(-1)
+				continue
+			case ui.KeyDown:
+				t.suggest.move(1)
+				continue
+			case ui.KeyEnter, ui.KeyTab:
+				t.acceptSuggestion(state, t.suggest.selected())
+				continue
+			case ui.KeyEscape:
+				t.suggest.close()
+				continue
+			}
+		}
 		switch k {
 		case ui.KeyLeft:
 			t.cursor = t.prev(state)
@@ -174,7 +340,11 @@ func (t *TextField) handleKeyEvents(state *ui.State) {
 		case ui.KeyEnter:
 			t.TriggerAction(state)
 			continue
+		case ui.KeyMenu:
+			menu = true
+			continue
 		default:
+			state.IgnoreKeyPress(k)
 			continue
 		}
 		if !state.HasModifiers(ui.Shift) {
@@ -182,6 +352,7 @@ func (t *TextField) handleKeyEvents(state *ui.State) {
 		}
 		state.SetBlink()
 	}
+	return
 }
 
 func (t *TextField) SelectAll(state *ui.State) {
@@ -190,11 +361,17 @@ func (t *TextField) SelectAll(state *ui.State) {
 }
 
 func (t *TextField) Cut(state *ui.State) {
+	if t.Password {
+		return
+	}
 	state.SetClipboardString(t.SelectedText())
 	t.insert("")
 }
 
 func (t *TextField) Copy(state *ui.State) {
+	if t.Password {
+		return
+	}
 	state.SetClipboardString(t.SelectedText())
 }
 
@@ -226,6 +403,9 @@ func (t *TextField) next(state *ui.State) int {
 		return len(t.Text)
 	}
 	if state.HasModifiers(ui.Control) {
+		if t.Password {
+			return len(t.Text)
+		}
 		return text.NextWord(t.Text, t.cursor)
 	} else {
 		_, size := utf8.DecodeRuneInString(t.Text[t.cursor:])
@@ -238,6 +418,9 @@ func (t *TextField) prev(state *ui.State) int {
 		t.cursor = len(t.Text)
 	}
 	if state.HasModifiers(ui.Control) {
+		if t.Password {
+			return 0
+		}
 		return text.PreviousWord(t.Text, t.cursor)
 	} else {
 		_, size := utf8.DecodeLastRuneInString(t.Text[:t.cursor])
@@ -250,13 +433,41 @@ func (t *TextField) insert(s string) {
 		return
 	}
 	s1, s2 := t.selection()
+	if s != "" && t.Filter != nil {
+		s = t.Filter(s)
+	}
+	if t.MaxLength > 0 {
+		n := t.MaxLength - utf8.RuneCountInString(t.Text[:s1]) - utf8.RuneCountInString(t.Text[s2:])
+		for i := range s {
+			if n <= 0 {
+				s = s[:i]
+				break
+			}
+			n--
+		}
+	}
 	t.Text = t.Text[:s1] + s + t.Text[s2:]
 	t.cursor = s1 + len(s)
 	t.selectionStart = t.cursor
+	t.edited = true
+}
+
+func (t *TextField) acceptSuggestion(state *ui.State, item ListItem) {
+	t.suggest.close()
+	if !t.Editable {
+		return
+	}
+	// the suggestion replaces the text before the cursor, like typed text it is filtered and limited to MaxLength
+	t.selection() // make sure the cursor is valid
+	t.selectionStart = 0
+	t.insert(item.Text)
+	// the suggestions are not shown again until the user edits the text
+	t.edited = false
 }
 
 const (
 	tfIdle = iota
 	tfSelect
 	tfDrag
+	tfRect
 )
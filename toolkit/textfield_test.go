@@ -0,0 +1,34 @@
+package toolkit
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jfreymuth/ui"
+)
+
+func TestTextFieldAcceptSuggestion(t *testing.T) {
+	var st ui.BackendState
+	tests := []struct {
+		setup  func(*TextField)
+		text   string
+		cursor int
+		item   string
+		want   string
+	}{
+		{func(*TextField) {}, "abxy", 2, "abc", "abcxy"},
+		{func(t *TextField) { t.Filter = strings.ToUpper }, "ab", 2, "abc", "ABC"},
+		{func(t *TextField) { t.MaxLength = 4 }, "ab", 2, "abcdef", "abcd"},
+		{func(t *TextField) { t.MaxLength = 4 }, "abxy", 2, "abcdef", "abxy"},
+		{func(t *TextField) { t.Editable = false }, "ab", 2, "abc", "ab"},
+	}
+	for _, tt := range tests {
+		f := NewTextField()
+		tt.setup(f)
+		f.Text, f.cursor, f.selectionStart = tt.text, tt.cursor, tt.cursor
+		f.acceptSuggestion(&st.State, ListItem{Text: tt.item})
+		if f.Text != tt.want {
+			t.Errorf("%q + %q = %q, want %q", tt.text, tt.item, f.Text, tt.want)
+		}
+	}
+}
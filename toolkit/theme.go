@@ -8,6 +8,7 @@ import (
 type Theme struct {
 	fonts  map[string]draw.Font
 	colors map[string]draw.Color
+	sizes  map[string]int
 	parent *Theme
 }
 
@@ -41,6 +42,17 @@ func (t *Theme) Color(name string) draw.Color {
 	return draw.Transparent
 }
 
+// Size returns a size in pixels, like the corner radius of buttons ("cornerRadius").
+func (t *Theme) Size(name string) int {
+	if s, ok := t.sizes[name]; ok {
+		return s
+	}
+	if t.parent != nil {
+		return t.parent.Size(name)
+	}
+	return 0
+}
+
 func (t *Theme) SetFont(name string, f draw.Font) {
 	if t.fonts == nil {
 		t.fonts = make(map[string]draw.Font)
@@ -55,6 +67,13 @@ func (t *Theme) SetColor(name string, c draw.Color) {
 	t.colors[name] = c
 }
 
+func (t *Theme) SetSize(name string, s int) {
+	if t.sizes == nil {
+		t.sizes = make(map[string]int)
+	}
+	t.sizes[name] = s
+}
+
 var DefaultTheme = LightTheme
 var LightTheme = &Theme{
 	fonts: map[string]draw.Font{
@@ -70,6 +89,8 @@ var LightTheme = &Theme{
 		"veil":                 draw.RGBA(0, 0, 0, .2),
 		"altBackground":        draw.Gray(.9),
 		"text":                 draw.Black,
+		"textDisabled":         draw.Gray(.6),
+		"separator":            draw.Gray(.8),
 		"title":                draw.Black,
 		"titleBackground":      draw.RGBA(.7, .75, 1, 1),
 		"titleBackgroundError": draw.RGBA(1, .75, .7, 1),
@@ -79,10 +100,16 @@ var LightTheme = &Theme{
 		"buttonFocused":        draw.Gray(.3),
 		"inputBackground":      draw.White,
 		"inputText":            draw.Black,
+		"inputError":           draw.RGBA(.8, 0, 0, 1),
+		"placeholder":          draw.Gray(.5),
 		"selection":            draw.RGBA(.8, .85, 1, 1),
 		"selectionInactive":    draw.Gray(.8),
+		"highlight":            draw.RGBA(1, .85, .3, 1),
 		"scrollBar":            draw.RGBA(0, 0, 0, .3),
 	},
+	sizes: map[string]int{
+		"cornerRadius": 4,
+	},
 }
 var DarkTheme = &Theme{
 	fonts: map[string]draw.Font{
@@ -98,6 +125,8 @@ var DarkTheme = &Theme{
 		"veil":                 draw.RGBA(1, 1, 1, .2),
 		"altBackground":        draw.Gray(.4),
 		"text":                 draw.White,
+		"textDisabled":         draw.Gray(.6),
+		"separator":            draw.Gray(.45),
 		"title":                draw.White,
 		"titleBackground":      draw.RGBA(.2, .25, .4, 1),
 		"titleBackgroundError": draw.RGBA(.5, .05, 0, 1),
@@ -107,8 +136,14 @@ var DarkTheme = &Theme{
 		"buttonFocused":        draw.Gray(.8),
 		"inputBackground":      draw.Gray(.3),
 		"inputText":            draw.White,
+		"inputError":           draw.RGBA(1, .4, .3, 1),
+		"placeholder":          draw.Gray(.6),
 		"selection":            draw.RGBA(.35, .4, .6, 1),
 		"selectionInactive":    draw.Gray(.5),
+		"highlight":            draw.RGBA(.6, .45, .1, 1),
 		"scrollBar":            draw.RGBA(1, 1, 1, .3),
 	},
+	sizes: map[string]int{
+		"cornerRadius": 4,
+	},
 }
@@ -0,0 +1,239 @@
+package toolkit
+
+import (
+	"image"
+
+	"github.com/jfreymuth/ui"
+	"github.com/jfreymuth/ui/draw"
+	"github.com/jfreymuth/ui/text"
+)
+
+// A ToolBar is a row of buttons and other components.
+// Items that do not fit are moved into a popup menu, which is opened with a button at the end of the tool bar.
+type ToolBar struct {
+	Theme    *Theme
+	items    []ui.Component
+	visible  int // number of items that are not in the overflow menu
+	overflow toolBarOverflow
+	menu     *Menu
+}
+
+func NewToolBar() *ToolBar {
+	t := &ToolBar{Theme: DefaultTheme}
+	t.overflow.t = t
+	return t
+}
+
+func (t *ToolBar) SetTheme(theme *Theme) {
+	t.Theme = theme
+	for _, c := range t.items {
+		SetTheme(c, theme)
+	}
+}
+
+// AddButton adds a button showing an icon. The text is shown as a tooltip and in the overflow menu.
+func (t *ToolBar) AddButton(icon, text string, action func(*ui.State)) *ToolButton {
+	b := &ToolButton{Theme: t.Theme, Icon: icon, Text: text, Action: action}
+	t.items = append(t.items, b)
+	return b
+}
+
+// AddToggleButton adds a button that switches its Checked state when it is clicked.
+func (t *ToolBar) AddToggleButton(icon, text string, checked bool, action func(*ui.State)) *ToolButton {
+	b := &ToolButton{Theme: t.Theme, Icon: icon, Text: text, Action: action, Toggle: true, Checked: checked}
+	t.items = append(t.items, b)
+	return b
+}
+
+func (t *ToolBar) AddSeparator() {
+	t.items = append(t.items, &toolBarSeparator{t})
+}
+
+// AddComponent adds an arbitrary component, e.g. a ComboBox.
+// If text is not empty, a label is shown in front of the component.
+func (t *ToolBar) AddComponent(text string, c ui.Component) {
+	if text != "" {
+		c = &Container{Left: NewPadding(&Label{Theme: t.Theme, Text: text}, 3), Center: c}
+	}
+	SetTheme(c, t.Theme)
+	t.items = append(t.items, c)
+}
+
+func (t *ToolBar) PreferredSize(fonts draw.FontLookup) (int, int) {
+	w, h := 0, 0
+	for _, c := range t.items {
+		cw, ch := c.PreferredSize(fonts)
+		w += cw
+		if ch > h {
+			h = ch
+		}
+	}
+	if _, oh := t.overflow.PreferredSize(fonts); oh > h {
+		h = oh
+	}
+	return w, h
+}
+
+func (t *ToolBar) Update(g *draw.Buffer, state *ui.State) {
+	w, h := g.Size()
+	g.Fill(draw.WH(w, h), t.Theme.Color("altBackground"))
+	ws := make([]int, len(t.items))
+	total := 0
+	for i, c := range t.items {
+		ws[i], _ = c.PreferredSize(g.FontLookup)
+		total += ws[i]
+	}
+	ow, _ := t.overflow.PreferredSize(g.FontLookup)
+	// while the overflow menu is open, the items it contains must not be updated by the tool bar
+	if !state.HasPopups() {
+		t.visible = len(t.items)
+		if total > w {
+			x := 0
+			for i := range t.items {
+				if x+ws[i] > w-ow {
+					t.visible = i
+					break
+				}
+				x += ws[i]
+			}
+		}
+	}
+	if t.visible > len(t.items) {
+		t.visible = len(t.items)
+	}
+	x := 0
+	for i, c := range t.items[:t.visible] {
+		if _, ok := c.(*toolBarSeparator); ok && i == t.visible-1 && t.visible < len(t.items) {
+			break
+		}
+		state.UpdateChild(g, draw.XYWH(x, 0, ws[i], h), c)
+		x += ws[i]
+	}
+	// buttons in the overflow menu are not updated, so they can not close their own tooltips
+	for _, c := range t.items[t.visible:] {
+		if b, ok := c.(*ToolButton); ok {
+			b.tip.close()
+		}
+	}
+	if t.visible < len(t.items) {
+		state.UpdateChild(g, draw.XYWH(w-ow, 0, ow, h), &t.overflow)
+	}
+}
+
+// openOverflow shows the items that do not fit in a popup menu.
+func (t *ToolBar) openOverflow(p image.Point, state *ui.State, fonts draw.FontLookup) {
+	if t.menu == nil {
+		t.menu = NewPopupMenu("")
+	}
+	t.menu.Clear()
+	sep := true
+	for _, c := range t.items[t.visible:] {
+		switch c := c.(type) {
+		case *toolBarSeparator:
+			if !sep {
+				t.menu.AddSeparator()
+				sep = true
+			}
+			continue
+		case *ToolButton:
+			var i *MenuItem
+			if c.Toggle {
+				i = t.menu.AddCheckItem(c.Text, c.Checked, c.activate)
+			} else {
+				i = t.menu.AddItemIcon(c.Icon, c.Text, c.activate)
+			}
+			i.Disabled = c.Disabled
+		default:
+			t.menu.items = append(t.menu.items, NewPadding(c, 3))
+		}
+		sep = false
+	}
+	if sep && len(t.menu.items) > 0 {
+		t.menu.items = t.menu.items[:len(t.menu.items)-1]
+	}
+	t.menu.SetTheme(t.Theme)
+	t.menu.OpenPopupMenu(p, state, fonts)
+}
+
+// A ToolButton is a button in a ToolBar.
+type ToolButton struct {
+	Theme *Theme
+	Icon  string
+	// Text is shown as a tooltip, and in the overflow menu.
+	Text string
+	// Toggle buttons switch their Checked state when they are clicked.
+	Toggle   bool
+	Checked  bool
+	Disabled bool
+	Action   func(*ui.State)
+	tip      tooltip
+	anim     float32
+}
+
+func (b *ToolButton) SetTheme(theme *Theme) { b.Theme = theme }
+
+func (b *ToolButton) PreferredSize(fonts draw.FontLookup) (int, int) {
+	h := fonts.Metrics(b.Theme.Font("buttonText")).LineHeight()
+	return h + 12, h + 12
+}
+
+func (b *ToolButton) Update(g *draw.Buffer, state *ui.State) {
+	w, h := g.Size()
+	if b.Disabled {
+		g.Icon(draw.XYXY(6, 6, w-6, h-6), b.Icon, b.Theme.Color("textDisabled"))
+		b.tip.close()
+		return
+	}
+	if b.Checked {
+		g.RoundedFill(draw.XYXY(2, 2, w-2, h-2), b.Theme.Size("cornerRadius"), b.Theme.Color("selection"))
+	}
+	animate(state, &b.anim, 8, state.IsHovered())
+	g.RoundedFill(draw.XYXY(2, 2, w-2, h-2), b.Theme.Size("cornerRadius"), draw.Blend(b.Theme.Color("buttonBackground"), b.Theme.Color("buttonHovered"), b.anim))
+	g.Icon(draw.XYXY(6, 6, w-6, h-6), b.Icon, b.Theme.Color("buttonText"))
+	b.tip.update(g, state, b.Text, b.Theme)
+	if state.MouseClick(ui.MouseLeft) {
+		b.activate(state)
+		state.RequestUpdate()
+	}
+}
+
+func (b *ToolButton) activate(state *ui.State) {
+	if b.Toggle {
+		b.Checked = !b.Checked
+	}
+	if b.Action != nil {
+		b.Action(state)
+	}
+}
+
+type toolBarSeparator struct {
+	t *ToolBar
+}
+
+func (*toolBarSeparator) PreferredSize(draw.FontLookup) (int, int) { return 9, 0 }
+func (s *toolBarSeparator) Update(g *draw.Buffer, state *ui.State) {
+	_, h := g.Size()
+	g.Fill(draw.XYWH(4, 4, 1, h-8), s.t.Theme.Color("separator"))
+}
+
+type toolBarOverflow struct {
+	t    *ToolBar
+	text text.Text
+	anim float32
+}
+
+func (b *toolBarOverflow) PreferredSize(fonts draw.FontLookup) (int, int) {
+	w, h := b.text.Size("»", b.t.Theme.Font("buttonText"), fonts)
+	return w + 12, h + 12
+}
+
+func (b *toolBarOverflow) Update(g *draw.Buffer, state *ui.State) {
+	w, h := g.Size()
+	animate(state, &b.anim, 8, state.IsHovered())
+	g.RoundedFill(draw.XYXY(2, 2, w-2, h-2), b.t.Theme.Size("cornerRadius"), draw.Blend(b.t.Theme.Color("buttonBackground"), b.t.Theme.Color("buttonHovered"), b.anim))
+	b.text.DrawCentered(g, draw.WH(w, h), "»", b.t.Theme.Font("buttonText"), b.t.Theme.Color("buttonText"))
+	if state.MouseButtonDown(ui.MouseLeft) && !state.HasPopups() {
+		b.t.openOverflow(image.Pt(0, h), state, g.FontLookup)
+		state.InitiateDrag(ui.MenuDrag)
+	}
+}
@@ -0,0 +1,52 @@
+package toolkit
+
+import (
+	"image"
+
+	"github.com/jfreymuth/ui"
+	"github.com/jfreymuth/ui/draw"
+)
+
+// A tooltip shows a short text below a component after the mouse has rested on it for a moment.
+type tooltip struct {
+	label Label
+	delay float32
+	popup ui.Popup
+}
+
+// update must be called from the Update method of the component the tooltip belongs to.
+func (t *tooltip) update(g *draw.Buffer, state *ui.State, text string, theme *Theme) {
+	if text == "" || !state.IsHovered() || state.MouseButtonDown(ui.MouseLeft) || state.HasPopups() {
+		t.close()
+		return
+	}
+	animate(state, &t.delay, 2, true)
+	if t.delay < 1 || isOpen(t.popup) {
+		return
+	}
+	t.label.Theme, t.label.Text = theme, text
+	c := &menuBackground{NewPadding(&t.label, 3), theme}
+	pw, ph := c.PreferredSize(g.FontLookup)
+	_, h := g.Size()
+	win := state.WindowBounds()
+	r := draw.XYWH(0, h, pw, ph)
+	if r.Max.Y > win.Max.Y {
+		r = draw.XYWH(0, -ph, pw, ph)
+	}
+	if r.Max.X > win.Max.X {
+		r = r.Sub(image.Pt(r.Max.X-win.Max.X, 0))
+	}
+	if r.Min.X < win.Min.X {
+		r = r.Add(image.Pt(win.Min.X-r.Min.X, 0))
+	}
+	t.popup = state.OpenNonModalPopup(r, c)
+}
+
+// close closes the tooltip. It should also be called when the component is not drawn.
+func (t *tooltip) close() {
+	t.delay = 0
+	if t.popup != nil {
+		t.popup.Close()
+		t.popup = nil
+	}
+}
@@ -12,13 +12,22 @@ type Component interface {
 }
 
 type Root interface {
+	// OpenDialog opens a dialog on top of any open dialogs.
 	OpenDialog(Component)
+	// CloseDialog closes the topmost dialog.
 	CloseDialog()
 	OpenPopup(image.Rectangle, Component) Popup
 	ClosePopups()
 	HasPopups() bool
 }
 
+// A NonModalRoot is a Root that can also open popups that do not take the keyboard focus.
+// Implementing this interface is optional, State.OpenNonModalPopup does nothing if the root does not.
+type NonModalRoot interface {
+	Root
+	OpenNonModalPopup(image.Rectangle, Component) Popup
+}
+
 type Popup interface {
 	Close()
 	Closed() bool